@@ -0,0 +1,54 @@
+package capsolver
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Package represents a subscription package of the account.
+type Package struct {
+	// PackageID is the ID of the package.
+	PackageID string `json:"packageId"`
+	// Type is the package type.
+	Type int `json:"type"`
+	// Title is the display name of the package.
+	Title string `json:"title"`
+	// NumberOfCalls is the number of remaining calls.
+	NumberOfCalls int `json:"numberOfCalls"`
+	// Status is the package status (1 for active).
+	Status int `json:"status"`
+	// Token is the package token.
+	Token string `json:"token"`
+	// ActiveTime is the activation timestamp in seconds since epoch.
+	ActiveTime int64 `json:"activeTime"`
+	// ExpireTime is the expiration timestamp in seconds since epoch.
+	ExpireTime int64 `json:"expireTime"`
+}
+
+// Balance represents the response of the getBalance endpoint.
+type Balance struct {
+	// Balance is the account balance in USD.
+	Balance float64 `json:"balance"`
+	// Packages are the subscription packages of the account.
+	Packages []Package `json:"packages"`
+}
+
+// BalanceHook is called when the balance drops below the configured threshold.
+// It runs synchronously on the calling goroutine and should return quickly.
+type BalanceHook func(ctx context.Context, balance Balance)
+
+// GetBalance returns the account balance and subscription packages.
+func (s *Session) GetBalance(ctx context.Context) (*Balance, error) {
+	res, err := s.do(ctx, endpointGetBalance, Payload{})
+	if err != nil {
+		return nil, err
+	}
+	var balance Balance
+	if err := json.Unmarshal(res.Raw, &balance); err != nil {
+		return nil, err
+	}
+	if s.balanceHook != nil && balance.Balance < s.balanceThreshold {
+		s.balanceHook(ctx, balance)
+	}
+	return &balance, nil
+}
@@ -0,0 +1,58 @@
+package capsolver_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nukilabs/capsolver"
+	"github.com/nukilabs/capsolver/capsolvertest"
+)
+
+// balanceHook returns a BalanceHook that records the balances it is called with.
+func balanceHook(balances *[]capsolver.Balance) capsolver.BalanceHook {
+	return func(ctx context.Context, balance capsolver.Balance) {
+		*balances = append(*balances, balance)
+	}
+}
+
+func TestBalanceHookThreshold(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	var balances []capsolver.Balance
+	s := srv.Session(capsolver.WithBalanceHook(10, balanceHook(&balances)))
+	ctx := context.Background()
+
+	srv.SetBalance(capsolver.Balance{Balance: 20})
+	balance, err := s.GetBalance(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Balance != 20 || len(balances) != 0 {
+		t.Fatalf("balance = %v, hook calls = %v, want 20 without a hook call", balance.Balance, balances)
+	}
+
+	srv.SetBalance(capsolver.Balance{Balance: 5, Packages: []capsolver.Package{{PackageID: "p1"}}})
+	if _, err := s.GetBalance(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(balances) != 1 || balances[0].Balance != 5 || len(balances[0].Packages) != 1 {
+		t.Errorf("hook calls = %+v, want one with the balance of 5", balances)
+	}
+}
+
+func TestBalanceHookZeroBalance(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(image.TaskType(), capsolvertest.Behavior{Error: capsolver.ZeroBalance})
+	var balances []capsolver.Balance
+	s := srv.Session(capsolver.WithBalanceHook(10, balanceHook(&balances)))
+
+	if _, err := capsolver.SolveTask[capsolver.ImageToTextSolution](context.Background(), s, image); !errors.Is(err, capsolver.ZeroBalance) {
+		t.Fatalf("err = %v, want %s", err, capsolver.ZeroBalance)
+	}
+	if len(balances) != 1 || balances[0].Balance != 0 {
+		t.Errorf("hook calls = %+v, want one with a zero balance", balances)
+	}
+	if n := countRequests(srv, "/getBalance"); n != 0 {
+		t.Errorf("getBalance requests = %d, want 0", n)
+	}
+}
@@ -0,0 +1,68 @@
+package capsolver
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultBatchConcurrency is the concurrency used when BatchOptions.Concurrency is not set.
+const DefaultBatchConcurrency = 10
+
+// BatchOptions configures SolveBatch and SolveStream.
+type BatchOptions struct {
+	// Concurrency is the maximum number of tasks solved at once.
+	Concurrency int
+}
+
+// BatchResult is the outcome of a single task of a batch.
+type BatchResult struct {
+	// Index is the position of the task in the input slice.
+	Index int
+	// Result is the solved result, nil if Err is set.
+	Result *Result
+	// Err is the error of this task.
+	Err error
+}
+
+// SolveBatch solves the tasks with bounded concurrency and returns
+// the results in input order. Failed tasks carry their error in BatchResult.Err.
+func (s *Session) SolveBatch(ctx context.Context, tasks []Task, opts BatchOptions) []BatchResult {
+	results := make([]BatchResult, len(tasks))
+	for res := range s.SolveStream(ctx, tasks, opts) {
+		results[res.Index] = res
+	}
+	return results
+}
+
+// SolveStream is like SolveBatch but emits every result on the returned
+// channel as soon as its task completes. The channel is closed when all
+// tasks are done. Tasks not started before ctx is done fail with ctx.Err().
+func (s *Session) SolveStream(ctx context.Context, tasks []Task, opts BatchOptions) <-chan BatchResult {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	out := make(chan BatchResult, len(tasks))
+	go func() {
+		defer close(out)
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, concurrency)
+		for i, task := range tasks {
+			select {
+			case <-ctx.Done():
+				out <- BatchResult{Index: i, Err: ctx.Err()}
+				continue
+			case sem <- struct{}{}:
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				res, err := s.SolveContext(ctx, task)
+				out <- BatchResult{Index: i, Result: res, Err: err}
+			}()
+		}
+		wg.Wait()
+	}()
+	return out
+}
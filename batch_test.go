@@ -0,0 +1,138 @@
+package capsolver_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nukilabs/capsolver"
+	"github.com/nukilabs/capsolver/capsolvertest"
+)
+
+// concurrency is a middleware that records the peak number of requests in flight.
+type concurrency struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (c *concurrency) middleware(next capsolver.Handler) capsolver.Handler {
+	return func(ctx context.Context, endpoint string, payload capsolver.Payload) (*capsolver.Result, error) {
+		c.mu.Lock()
+		c.inFlight++
+		c.peak = max(c.peak, c.inFlight)
+		c.mu.Unlock()
+		defer func() {
+			c.mu.Lock()
+			c.inFlight--
+			c.mu.Unlock()
+		}()
+		return next(ctx, endpoint, payload)
+	}
+}
+
+func TestSolveBatchOrder(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle("ImageToTextTask", capsolvertest.Behavior{Solution: capsolver.ImageToTextSolution{Text: "abc"}, Sync: true, Latency: 5 * time.Millisecond})
+	srv.Handle("AntiTurnstileTaskProxyLess", capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}, Polls: 2})
+
+	var tasks []capsolver.Task
+	for i := range 12 {
+		switch i % 3 {
+		case 0:
+			tasks = append(tasks, capsolver.ImageToTextTask{Body: "aW1hZ2U="})
+		case 1:
+			tasks = append(tasks, turnstile)
+		default:
+			tasks = append(tasks, capsolver.GeeTestTask{WebsiteURL: "https://example.com"})
+		}
+	}
+	results := srv.Session().SolveBatch(context.Background(), tasks, capsolver.BatchOptions{Concurrency: 4})
+	if len(results) != len(tasks) {
+		t.Fatalf("got %d results, want %d", len(results), len(tasks))
+	}
+	for i, res := range results {
+		if res.Index != i {
+			t.Errorf("results[%d].Index = %d", i, res.Index)
+		}
+		switch i % 3 {
+		case 0:
+			var sol capsolver.ImageToTextSolution
+			if res.Err != nil || res.Result.Unmarshal(&sol) != nil || sol.Text != "abc" {
+				t.Errorf("results[%d] = %+v, want image solution", i, res)
+			}
+		case 1:
+			var sol capsolver.AntiTurnstileSolution
+			if res.Err != nil || res.Result.Unmarshal(&sol) != nil || sol.Token != "token" {
+				t.Errorf("results[%d] = %+v, want turnstile solution", i, res)
+			}
+		default:
+			if !errors.Is(res.Err, capsolver.TaskNotSupported) || res.Result != nil {
+				t.Errorf("results[%d] = %+v, want %s", i, res, capsolver.TaskNotSupported)
+			}
+		}
+	}
+}
+
+func TestSolveBatchConcurrency(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle("ImageToTextTask", capsolvertest.Behavior{Solution: capsolver.ImageToTextSolution{Text: "abc"}, Sync: true, Latency: 10 * time.Millisecond})
+	var c concurrency
+	s := srv.Session(capsolver.WithMiddleware(c.middleware))
+
+	tasks := make([]capsolver.Task, 20)
+	for i := range tasks {
+		tasks[i] = capsolver.ImageToTextTask{Body: "aW1hZ2U="}
+	}
+	for _, res := range s.SolveBatch(context.Background(), tasks, capsolver.BatchOptions{Concurrency: 3}) {
+		if res.Err != nil {
+			t.Fatal(res.Err)
+		}
+	}
+	if c.peak != 3 {
+		t.Errorf("peak concurrency = %d, want 3", c.peak)
+	}
+}
+
+func TestSolveStream(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle("ImageToTextTask", capsolvertest.Behavior{Solution: capsolver.ImageToTextSolution{Text: "abc"}, Sync: true})
+	tasks := []capsolver.Task{
+		capsolver.ImageToTextTask{Body: "aW1hZ2U="},
+		capsolver.ImageToTextTask{Body: "aW1hZ2U="},
+		capsolver.ImageToTextTask{Body: "aW1hZ2U="},
+	}
+
+	seen := make(map[int]bool)
+	for res := range srv.Session().SolveStream(context.Background(), tasks, capsolver.BatchOptions{}) {
+		if res.Err != nil {
+			t.Fatal(res.Err)
+		}
+		if seen[res.Index] {
+			t.Errorf("index %d emitted twice", res.Index)
+		}
+		seen[res.Index] = true
+	}
+	if len(seen) != len(tasks) {
+		t.Errorf("got %d results, want %d", len(seen), len(tasks))
+	}
+}
+
+func TestSolveBatchCancelled(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle("ImageToTextTask", capsolvertest.Behavior{Solution: capsolver.ImageToTextSolution{Text: "abc"}, Sync: true})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tasks := []capsolver.Task{capsolver.ImageToTextTask{Body: "aW1hZ2U="}, capsolver.ImageToTextTask{Body: "aW1hZ2U="}}
+	for i, res := range srv.Session().SolveBatch(ctx, tasks, capsolver.BatchOptions{Concurrency: 1}) {
+		if res.Index != i || !errors.Is(res.Err, context.Canceled) {
+			t.Errorf("results[%d] = %+v, want %v", i, res, context.Canceled)
+		}
+	}
+	if n := countRequests(srv, "/createTask"); n != 0 {
+		t.Errorf("createTask requests = %d, want 0", n)
+	}
+}
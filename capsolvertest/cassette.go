@@ -0,0 +1,259 @@
+package capsolvertest
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/nukilabs/capsolver"
+)
+
+// ErrNotRecorded is returned by a Replayer for requests that are not in the cassette.
+var ErrNotRecorded = errors.New("capsolvertest: request not recorded in cassette")
+
+// Exchange is a recorded request and response, stored as one JSONL line.
+type Exchange struct {
+	// Endpoint is the endpoint that was called (e.g. "/createTask").
+	Endpoint string `json:"endpoint"`
+	// Request is the normalized payload, without client key and app ID.
+	Request json.RawMessage `json:"request"`
+	// Response is the response body as returned by the API.
+	Response json.RawMessage `json:"response"`
+}
+
+// CassetteOption configures a CassetteRecorder or Replayer.
+type CassetteOption func(*cassetteConfig)
+
+type cassetteConfig struct {
+	truncate int
+}
+
+// TruncateImages replaces strings longer than n bytes, such as base64 images,
+// with a prefix and their SHA-256 hash. Recorder and replayer must use the same n.
+func TruncateImages(n int) CassetteOption {
+	return func(c *cassetteConfig) {
+		c.truncate = n
+	}
+}
+
+// CassetteRecorder writes the exchanges passing through its middleware to a JSONL file.
+// Only requests that got a response from the API are recorded. Failures to write
+// the cassette never fail the request; the first one is returned by Close.
+type CassetteRecorder struct {
+	cfg cassetteConfig
+
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+	err  error
+}
+
+// NewCassetteRecorder creates or truncates the cassette file at path.
+func NewCassetteRecorder(path string, opts ...CassetteOption) (*CassetteRecorder, error) {
+	r := &CassetteRecorder{}
+	for _, opt := range opts {
+		opt(&r.cfg)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	r.file = f
+	r.enc = json.NewEncoder(f)
+	return r, nil
+}
+
+// Middleware returns the middleware that records into the cassette.
+func (r *CassetteRecorder) Middleware() capsolver.Middleware {
+	return func(next capsolver.Handler) capsolver.Handler {
+		return func(ctx context.Context, endpoint string, payload capsolver.Payload) (*capsolver.Result, error) {
+			res, err := next(ctx, endpoint, payload)
+			if err != nil || res == nil {
+				return res, err
+			}
+			r.record(endpoint, payload, res)
+			return res, nil
+		}
+	}
+}
+
+// record appends the exchange to the cassette, keeping the first error.
+func (r *CassetteRecorder) record(endpoint string, payload capsolver.Payload, res *capsolver.Result) {
+	req, err := normalize(payload, r.cfg.truncate)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err == nil {
+		err = r.enc.Encode(Exchange{Endpoint: endpoint, Request: req, Response: res.Raw})
+	}
+	if err != nil && r.err == nil {
+		r.err = fmt.Errorf("capsolvertest: record %s: %w", endpoint, err)
+	}
+}
+
+// Err returns the first error that occurred while writing the cassette.
+func (r *CassetteRecorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Close closes the cassette file. It returns the first error that occurred
+// while writing the cassette, if any.
+func (r *CassetteRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.file.Close(); err != nil && r.err == nil {
+		r.err = err
+	}
+	return r.err
+}
+
+// Replayer answers requests from a cassette without contacting the API.
+// Requests are matched on endpoint and normalized payload; every exchange
+// is replayed once, in recorded order.
+type Replayer struct {
+	t   testing.TB
+	cfg cassetteConfig
+
+	mu        sync.Mutex
+	exchanges []Exchange
+	used      []bool
+}
+
+// NewReplayer loads the cassette at path. If t is not nil, requests that are
+// not in the cassette are also reported with t.Errorf, failing the test.
+func NewReplayer(t testing.TB, path string, opts ...CassetteOption) (*Replayer, error) {
+	r := &Replayer{t: t}
+	for _, opt := range opts {
+		opt(&r.cfg)
+	}
+	if err := r.load(path); err != nil {
+		return nil, fmt.Errorf("capsolvertest: load cassette: %w", err)
+	}
+	return r, nil
+}
+
+func (r *Replayer) load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 64<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var ex Exchange
+		if err := json.Unmarshal(scanner.Bytes(), &ex); err != nil {
+			return fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		// Re-normalize so cassettes edited by hand still match.
+		var v any
+		if err := json.Unmarshal(ex.Request, &v); err != nil {
+			return fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		ex.Request, _ = json.Marshal(v)
+		r.exchanges = append(r.exchanges, ex)
+	}
+	r.used = make([]bool, len(r.exchanges))
+	return scanner.Err()
+}
+
+// Middleware returns the middleware that replays the cassette.
+// It never calls the next handler.
+func (r *Replayer) Middleware() capsolver.Middleware {
+	return func(capsolver.Handler) capsolver.Handler {
+		return func(ctx context.Context, endpoint string, payload capsolver.Payload) (*capsolver.Result, error) {
+			req, err := normalize(payload, r.cfg.truncate)
+			if err != nil {
+				return nil, err
+			}
+			ex, ok := r.take(endpoint, req)
+			if !ok {
+				err := fmt.Errorf("%w: %s %s", ErrNotRecorded, endpoint, req)
+				if r.t != nil {
+					r.t.Errorf("%v", err)
+				}
+				return nil, err
+			}
+			var res capsolver.Result
+			if err := json.Unmarshal(ex.Response, &res); err != nil {
+				return nil, err
+			}
+			res.Raw = ex.Response
+			return &res, nil
+		}
+	}
+}
+
+// Remaining returns the number of exchanges that have not been replayed.
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, used := range r.used {
+		if !used {
+			n++
+		}
+	}
+	return n
+}
+
+// take returns the first unused exchange matching the request and marks it used.
+func (r *Replayer) take(endpoint string, req json.RawMessage) (Exchange, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, ex := range r.exchanges {
+		if !r.used[i] && ex.Endpoint == endpoint && string(ex.Request) == string(req) {
+			r.used[i] = true
+			return ex, true
+		}
+	}
+	return Exchange{}, false
+}
+
+// normalize encodes the payload as canonical JSON without client key and app ID,
+// with proxy passwords redacted and long strings truncated if limit is positive.
+func normalize(payload capsolver.Payload, limit int) (json.RawMessage, error) {
+	payload = payload.Redacted()
+	payload.ClientKey = ""
+	payload.AppID = ""
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var v map[string]any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	delete(v, "clientKey")
+	return json.Marshal(truncateStrings(v, limit))
+}
+
+func truncateStrings(v any, limit int) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = truncateStrings(item, limit)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = truncateStrings(item, limit)
+		}
+	case string:
+		if limit > 0 && len(v) > limit {
+			sum := sha256.Sum256([]byte(v))
+			return v[:limit] + "...sha256:" + hex.EncodeToString(sum[:])
+		}
+	}
+	return v
+}
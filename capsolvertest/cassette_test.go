@@ -0,0 +1,134 @@
+package capsolvertest_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nukilabs/capsolver"
+	"github.com/nukilabs/capsolver/capsolvertest"
+)
+
+// record solves task against a fake server and records the exchanges to a new cassette.
+func record(t *testing.T, task capsolver.ImageToTextTask, opts ...capsolvertest.CassetteOption) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(task.TaskType(), capsolvertest.Behavior{Solution: capsolver.ImageToTextSolution{Text: "abc"}, Polls: 1})
+	rec, err := capsolvertest.NewCassetteRecorder(path, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.Session(capsolver.WithMiddleware(rec.Middleware())).SolveImageToTextContext(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// offline returns a session that cannot reach any API.
+func offline(mw capsolver.Middleware) *capsolver.Session {
+	return capsolver.New("other-key",
+		capsolver.WithBaseURL("http://127.0.0.1:0"),
+		capsolver.WithInitialDelay(0),
+		capsolver.WithPollInterval(1),
+		capsolver.WithRetryPolicy(capsolver.NoRetry),
+		capsolver.WithMiddleware(mw),
+	)
+}
+
+func TestCassetteRoundTrip(t *testing.T) {
+	task := capsolver.ImageToTextTask{Body: strings.Repeat("A", 1000), Module: "common"}
+	path := record(t, task, capsolvertest.TruncateImages(32))
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := bytes.Count(data, []byte("\n")); lines != 3 {
+		t.Errorf("cassette has %d lines, want 3", lines)
+	}
+	if bytes.Contains(data, []byte("clientKey")) || bytes.Contains(data, []byte(task.Body)) {
+		t.Errorf("cassette contains the client key or the full image:\n%s", data)
+	}
+
+	rp, err := capsolvertest.NewReplayer(t, path, capsolvertest.TruncateImages(32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sol, err := offline(rp.Middleware()).SolveImageToTextContext(ctx, task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sol.Solution.Text != "abc" || sol.TaskID != "capsolvertest-1" || sol.PollCount != 2 {
+		t.Errorf("solved = %+v, want abc from capsolvertest-1 after 2 polls", sol)
+	}
+	if n := rp.Remaining(); n != 0 {
+		t.Errorf("remaining = %d, want 0", n)
+	}
+}
+
+// recordingTB captures Errorf calls.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (tb *recordingTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+func TestCassetteNotRecorded(t *testing.T) {
+	task := capsolver.ImageToTextTask{Body: "aW1hZ2U=", Module: "common"}
+	path := record(t, task)
+
+	tb := &recordingTB{TB: t}
+	rp, err := capsolvertest.NewReplayer(tb, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task.Module = "number"
+	_, err = offline(rp.Middleware()).SolveImageToTextContext(ctx, task)
+	if !errors.Is(err, capsolvertest.ErrNotRecorded) {
+		t.Fatalf("err = %v, want %v", err, capsolvertest.ErrNotRecorded)
+	}
+	if !strings.Contains(err.Error(), `"module":"number"`) {
+		t.Errorf("err = %v, want the unmatched request body", err)
+	}
+	if len(tb.errors) != 1 {
+		t.Errorf("reported %d test errors, want 1", len(tb.errors))
+	}
+	if n := rp.Remaining(); n != 3 {
+		t.Errorf("remaining = %d, want 3", n)
+	}
+}
+
+func TestCassetteRecorderError(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle("ImageToTextTask", capsolvertest.Behavior{Solution: capsolver.ImageToTextSolution{Text: "abc"}, Sync: true})
+	rec, err := capsolvertest.NewCassetteRecorder(filepath.Join(t.TempDir(), "cassette.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Close()
+
+	// Writing to the closed cassette fails, but the solve must not.
+	if _, err := srv.Session(capsolver.WithMiddleware(rec.Middleware())).SolveImageToTextContext(ctx, capsolver.ImageToTextTask{Body: "aW1hZ2U="}); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Err() == nil {
+		t.Error("Err() = nil, want the write error")
+	}
+}
+
+func TestReplayerMissingCassette(t *testing.T) {
+	if _, err := capsolvertest.NewReplayer(nil, filepath.Join(t.TempDir(), "missing.jsonl")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("err = %v, want %v", err, os.ErrNotExist)
+	}
+}
@@ -0,0 +1,276 @@
+// Package capsolvertest provides an in-process fake CapSolver API for tests.
+//
+//	srv := capsolvertest.NewServer(t)
+//	srv.Handle("ReCaptchaV2TaskProxyLess", capsolvertest.Behavior{
+//		Solution: capsolver.ReCaptchaV2Solution{GRecaptchaResponse: "token"},
+//		Polls:    2,
+//	})
+//	sol, err := srv.Session().SolveReCaptchaV2(capsolver.ReCaptchaV2Task{...})
+package capsolvertest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nukilabs/capsolver"
+)
+
+// Behavior scripts how the server answers the tasks of a type.
+type Behavior struct {
+	// Solution is encoded as the solution of the task.
+	Solution any
+	// Sync returns the solution directly from createTask, like image tasks.
+	Sync bool
+	// Polls is the number of getTaskResult requests answered with "processing"
+	// before the solution is returned.
+	Polls int
+	// Error makes createTask fail with this code.
+	Error capsolver.ErrorCode
+	// PollError makes getTaskResult fail with this code once Polls are used up.
+	PollError capsolver.ErrorCode
+	// Latency delays every response for the task type.
+	Latency time.Duration
+	// HTTPStatus makes requests for the task type fail with this status and a non-JSON body.
+	HTTPStatus int
+	// HTTPFailures limits HTTPStatus to the first n requests; zero means all of them.
+	HTTPFailures int
+}
+
+// Request is a request received by the server.
+type Request struct {
+	// Endpoint is the endpoint that was called (e.g. "/createTask").
+	Endpoint string
+	// Payload is the decoded request body; Payload.Task is a map[string]any.
+	Payload capsolver.Payload
+}
+
+// Server is a fake CapSolver API backed by an httptest.Server.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	behaviors map[string]Behavior
+	fallback  *Behavior
+	failures  map[string]int
+	tasks     map[string]*task
+	requests  []Request
+	balance   capsolver.Balance
+	nextID    int
+}
+
+type task struct {
+	typ   string
+	polls int
+}
+
+// NewServer starts a fake server that is closed when the test ends.
+// Tasks without a Behavior fail with ERROR_TASK_NOT_SUPPORTED.
+func NewServer(t testing.TB) *Server {
+	s := &Server{
+		behaviors: make(map[string]Behavior),
+		failures:  make(map[string]int),
+		tasks:     make(map[string]*task),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Handle sets the behavior for a task type (e.g. "ReCaptchaV2TaskProxyLess").
+func (s *Server) Handle(taskType string, b Behavior) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.behaviors[taskType] = b
+}
+
+// HandleDefault sets the behavior for task types without their own behavior.
+func (s *Server) HandleDefault(b Behavior) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fallback = &b
+}
+
+// SetBalance sets the response of getBalance.
+func (s *Server) SetBalance(b capsolver.Balance) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.balance = b
+}
+
+// Requests returns the requests received so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Tasks returns the tasks of the given type received by createTask, in order.
+func (s *Server) Tasks(taskType string) []map[string]any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var tasks []map[string]any
+	for _, r := range s.requests {
+		if m, ok := r.Payload.Task.(map[string]any); ok && r.Endpoint == "/createTask" && m["type"] == taskType {
+			tasks = append(tasks, m)
+		}
+	}
+	return tasks
+}
+
+// Session returns a session pointed at the server that polls without delay.
+// The options are applied after the defaults.
+func (s *Server) Session(opts ...capsolver.Option) *capsolver.Session {
+	defaults := []capsolver.Option{
+		capsolver.WithBaseURL(s.URL),
+		capsolver.WithHTTPClient(s.Client()),
+		capsolver.WithPollInterval(time.Millisecond),
+		capsolver.WithInitialDelay(0),
+		capsolver.WithRetryPolicy(capsolver.RetryPolicy{
+			MaxAttempts: capsolver.DefaultRetryPolicy.MaxAttempts,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    10 * time.Millisecond,
+		}),
+	}
+	return capsolver.New("capsolvertest", append(defaults, opts...)...)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var payload capsolver.Payload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, capsolver.BadRequest, err.Error())
+		return
+	}
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Endpoint: r.URL.Path, Payload: payload})
+	s.mu.Unlock()
+
+	if payload.ClientKey == "" {
+		writeError(w, capsolver.KeyDeniedAccess, "clientKey is empty")
+		return
+	}
+	switch r.URL.Path {
+	case "/createTask":
+		s.createTask(w, r, payload)
+	case "/getTaskResult":
+		s.getTaskResult(w, r, payload)
+	case "/getBalance":
+		s.mu.Lock()
+		balance := s.balance
+		s.mu.Unlock()
+		writeJSON(w, map[string]any{"errorId": 0, "balance": balance.Balance, "packages": balance.Packages})
+	case "/feedbackTask":
+		writeJSON(w, map[string]any{"errorId": 0, "message": "ok"})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) createTask(w http.ResponseWriter, r *http.Request, payload capsolver.Payload) {
+	fields, _ := payload.Task.(map[string]any)
+	typ, _ := fields["type"].(string)
+	b, ok := s.behavior(typ)
+	if !ok {
+		writeError(w, capsolver.TaskNotSupported, fmt.Sprintf("task type %q is not handled", typ))
+		return
+	}
+	if !s.prepare(w, r, typ, b) {
+		return
+	}
+	if b.Error != "" {
+		writeError(w, b.Error, "injected by capsolvertest")
+		return
+	}
+
+	s.mu.Lock()
+	s.nextID++
+	id := fmt.Sprintf("capsolvertest-%d", s.nextID)
+	s.tasks[id] = &task{typ: typ}
+	s.mu.Unlock()
+
+	if b.Sync {
+		writeJSON(w, map[string]any{"errorId": 0, "taskId": id, "status": capsolver.StatusReady, "solution": b.Solution})
+		return
+	}
+	writeJSON(w, map[string]any{"errorId": 0, "taskId": id, "status": capsolver.StatusIdle})
+}
+
+func (s *Server) getTaskResult(w http.ResponseWriter, r *http.Request, payload capsolver.Payload) {
+	s.mu.Lock()
+	t, ok := s.tasks[payload.TaskID]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, capsolver.TaskIDInvalid, "task not found")
+		return
+	}
+	b, _ := s.behavior(t.typ)
+	if !s.prepare(w, r, t.typ, b) {
+		return
+	}
+
+	s.mu.Lock()
+	t.polls++
+	polls := t.polls
+	s.mu.Unlock()
+
+	switch {
+	case polls <= b.Polls:
+		writeJSON(w, map[string]any{"errorId": 0, "taskId": payload.TaskID, "status": capsolver.StatusProcessing})
+	case b.PollError != "":
+		writeError(w, b.PollError, "injected by capsolvertest")
+	default:
+		writeJSON(w, map[string]any{"errorId": 0, "taskId": payload.TaskID, "status": capsolver.StatusReady, "solution": b.Solution})
+	}
+}
+
+// behavior returns the behavior for the task type.
+func (s *Server) behavior(typ string) (Behavior, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b, ok := s.behaviors[typ]; ok {
+		return b, true
+	}
+	if s.fallback != nil {
+		return *s.fallback, true
+	}
+	return Behavior{}, false
+}
+
+// prepare applies the latency and HTTP failures of the behavior.
+// It reports false if the response has already been written.
+func (s *Server) prepare(w http.ResponseWriter, r *http.Request, typ string, b Behavior) bool {
+	if b.Latency > 0 {
+		select {
+		case <-time.After(b.Latency):
+		case <-r.Context().Done():
+			return false
+		}
+	}
+	if b.HTTPStatus == 0 {
+		return true
+	}
+	s.mu.Lock()
+	s.failures[typ]++
+	n := s.failures[typ]
+	s.mu.Unlock()
+	if b.HTTPFailures > 0 && n > b.HTTPFailures {
+		return true
+	}
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(b.HTTPStatus)
+	fmt.Fprintf(w, "<html><body>%d %s</body></html>", b.HTTPStatus, http.StatusText(b.HTTPStatus))
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code capsolver.ErrorCode, description string) {
+	writeJSON(w, map[string]any{"errorId": 1, "errorCode": code, "errorDescription": description})
+}
@@ -0,0 +1,212 @@
+package capsolvertest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/nukilabs/capsolver"
+	"github.com/nukilabs/capsolver/capsolvertest"
+)
+
+var (
+	ctx       = context.Background()
+	turnstile = capsolver.AntiTurnstileTask{WebsiteURL: "https://example.com", WebsiteKey: "key"}
+	solution  = capsolver.AntiTurnstileSolution{Token: "token"}
+)
+
+func count(srv *capsolvertest.Server, endpoint string) int {
+	n := 0
+	for _, r := range srv.Requests() {
+		if r.Endpoint == endpoint {
+			n++
+		}
+	}
+	return n
+}
+
+func TestServerPolls(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: solution, Polls: 3})
+
+	sol, err := srv.Session().SolveAntiTurnstileContext(ctx, turnstile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sol.Solution != solution || sol.TaskID != "capsolvertest-1" || sol.PollCount != 4 {
+		t.Errorf("solved = %+v, want %+v from capsolvertest-1 after 4 polls", sol, solution)
+	}
+	if n := count(srv, "/getTaskResult"); n != 4 {
+		t.Errorf("getTaskResult requests = %d, want 4", n)
+	}
+}
+
+func TestServerSync(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: solution, Sync: true, Polls: 3})
+
+	sol, err := srv.Session().SolveAntiTurnstileContext(ctx, turnstile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sol.Solution != solution || sol.PollCount != 0 {
+		t.Errorf("solved = %+v, want %+v without polls", sol, solution)
+	}
+	if n := count(srv, "/getTaskResult"); n != 0 {
+		t.Errorf("getTaskResult requests = %d, want 0", n)
+	}
+}
+
+func TestServerError(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Error: capsolver.ZeroBalance})
+
+	_, err := srv.Session().SolveAntiTurnstileContext(ctx, turnstile)
+	if !errors.Is(err, capsolver.ZeroBalance) {
+		t.Fatalf("err = %v, want %s", err, capsolver.ZeroBalance)
+	}
+	if n := count(srv, "/getTaskResult"); n != 0 {
+		t.Errorf("getTaskResult requests = %d, want 0", n)
+	}
+}
+
+func TestServerPollError(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Polls: 2, PollError: capsolver.CaptchaUnsolvable})
+
+	_, err := srv.Session().SolveAntiTurnstileContext(ctx, turnstile)
+	var apiErr capsolver.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != capsolver.CaptchaUnsolvable || apiErr.TaskID != "capsolvertest-1" {
+		t.Fatalf("err = %v, want %s for capsolvertest-1", err, capsolver.CaptchaUnsolvable)
+	}
+	if n := count(srv, "/getTaskResult"); n != 3 {
+		t.Errorf("getTaskResult requests = %d, want 3", n)
+	}
+}
+
+func TestServerHTTPFailures(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: solution, Sync: true, HTTPStatus: http.StatusBadGateway, HTTPFailures: 2})
+
+	sol, err := srv.Session().SolveAntiTurnstileContext(ctx, turnstile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sol.Solution != solution {
+		t.Errorf("solution = %+v, want %+v", sol.Solution, solution)
+	}
+	if n := count(srv, "/createTask"); n != 3 {
+		t.Errorf("createTask requests = %d, want 3", n)
+	}
+}
+
+func TestServerHTTPStatus(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: solution, HTTPStatus: http.StatusServiceUnavailable})
+
+	_, err := srv.Session().SolveAntiTurnstileContext(ctx, turnstile)
+	var httpErr *capsolver.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("err = %v, want HTTP 503", err)
+	}
+	if n, want := count(srv, "/createTask"), capsolver.DefaultRetryPolicy.MaxAttempts; n != want {
+		t.Errorf("createTask requests = %d, want %d", n, want)
+	}
+}
+
+func TestServerLatency(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: solution, Sync: true, Latency: 30 * time.Millisecond})
+
+	start := time.Now()
+	if _, err := srv.Session().SolveAntiTurnstileContext(ctx, turnstile); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond {
+		t.Errorf("elapsed = %s, want at least 30ms", elapsed)
+	}
+
+	short, cancel := context.WithTimeout(ctx, 5*time.Millisecond)
+	defer cancel()
+	if _, err := srv.Session().SolveAntiTurnstileContext(short, turnstile); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestServerFallback(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	s := srv.Session()
+
+	if _, err := s.SolveAntiTurnstileContext(ctx, turnstile); !errors.Is(err, capsolver.TaskNotSupported) {
+		t.Fatalf("err = %v, want %s", err, capsolver.TaskNotSupported)
+	}
+
+	srv.HandleDefault(capsolvertest.Behavior{Solution: solution, Sync: true})
+	sol, err := s.SolveAntiTurnstileContext(ctx, turnstile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sol.Solution != solution {
+		t.Errorf("solution = %+v, want %+v", sol.Solution, solution)
+	}
+
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Error: capsolver.InvalidTaskData})
+	if _, err := s.SolveAntiTurnstileContext(ctx, turnstile); !errors.Is(err, capsolver.InvalidTaskData) {
+		t.Errorf("err = %v, want %s", err, capsolver.InvalidTaskData)
+	}
+}
+
+func TestServerRequests(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: solution, Polls: 1})
+	srv.SetBalance(capsolver.Balance{Balance: 12.5})
+	s := srv.Session()
+
+	sol, err := s.SolveAntiTurnstileContext(ctx, turnstile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	balance, err := s.GetBalance(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Balance != 12.5 {
+		t.Errorf("balance = %v, want 12.5", balance.Balance)
+	}
+	if err := s.ReportResult(ctx, sol.TaskID, true, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetTaskResult(ctx, "unknown"); !errors.Is(err, capsolver.TaskIDInvalid) {
+		t.Errorf("err = %v, want %s", err, capsolver.TaskIDInvalid)
+	}
+
+	want := []string{"/createTask", "/getTaskResult", "/getTaskResult", "/getBalance", "/feedbackTask", "/getTaskResult"}
+	requests := srv.Requests()
+	if len(requests) != len(want) {
+		t.Fatalf("got %d requests, want %d", len(requests), len(want))
+	}
+	for i, r := range requests {
+		if r.Endpoint != want[i] {
+			t.Errorf("request %d endpoint = %s, want %s", i, r.Endpoint, want[i])
+		}
+		if r.Payload.ClientKey != "capsolvertest" {
+			t.Errorf("request %d client key = %q, want %q", i, r.Payload.ClientKey, "capsolvertest")
+		}
+	}
+	if requests[1].Payload.TaskID != sol.TaskID {
+		t.Errorf("polled task ID = %q, want %q", requests[1].Payload.TaskID, sol.TaskID)
+	}
+
+	tasks := srv.Tasks(turnstile.TaskType())
+	if len(tasks) != 1 {
+		t.Fatalf("got %d tasks, want 1", len(tasks))
+	}
+	if tasks[0]["websiteURL"] != turnstile.WebsiteURL || tasks[0]["websiteKey"] != turnstile.WebsiteKey {
+		t.Errorf("task = %v, want %+v", tasks[0], turnstile)
+	}
+	if other := srv.Tasks("ImageToTextTask"); len(other) != 0 {
+		t.Errorf("ImageToTextTask tasks = %v, want none", other)
+	}
+}
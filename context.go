@@ -1,21 +1,21 @@
-package capsolver
-
-import "context"
-
-type contextkey string
-
-const ContextKey contextkey = "capsolver"
-
-// WithContext wraps the given session with a context.
-func WithContext(ctx context.Context, s *Session) context.Context {
-	return context.WithValue(ctx, ContextKey, s)
-}
-
-// FromContext retrieves the session from the context.
-// This will return nil if the context does not contain a session.
-func FromContext(ctx context.Context) *Session {
-	if s, ok := ctx.Value(ContextKey).(*Session); ok {
-		return s
-	}
-	return nil
-}
+package capsolver
+
+import "context"
+
+type contextkey string
+
+const ContextKey contextkey = "capsolver"
+
+// WithContext wraps the given solver with a context.
+func WithContext(ctx context.Context, s Solver) context.Context {
+	return context.WithValue(ctx, ContextKey, s)
+}
+
+// FromContext retrieves the solver from the context.
+// This will return nil if the context does not contain a solver.
+func FromContext(ctx context.Context) Solver {
+	if s, ok := ctx.Value(ContextKey).(Solver); ok {
+		return s
+	}
+	return nil
+}
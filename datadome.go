@@ -1,34 +1,63 @@
-package capsolver
-
-// DataDomeSliderTask defines the parameters for a DataDome slider/interstitial task.
-type DataDomeSliderTask struct {
-	Type string `json:"type"`
-	// CaptchaURL is the URL to the DataDome captcha; must include t=fe.
-	CaptchaURL string `json:"captchaUrl"`
-	// UserAgent must match the UA used when requesting the target site.
-	UserAgent string `json:"userAgent"`
-	// Proxy is the proxy string (e.g., "http:ip:port:user:pass").
-	Proxy string `json:"proxy"`
-}
-
-// DataDomeSolution represents the solution returned by getTaskResult for DataDome.
-type DataDomeSolution struct {
-	// UserAgent echoes back the User-Agent used during solving.
-	UserAgent string `json:"userAgent"`
-	// Cookie is the DataDome cookie string to use for subsequent requests.
-	Cookie string `json:"cookie"`
-}
-
-// SolveDataDomeSlider submits a DataDomeSliderTask and returns the solution.
-func (s *Session) SolveDataDomeSlider(task DataDomeSliderTask) (*DataDomeSolution, error) {
-	task.Type = "DatadomeSliderTask"
-	res, err := s.createTask(task)
-	if err != nil {
-		return nil, err
-	}
-	var solution DataDomeSolution
-	if err := res.Unmarshal(&solution); err != nil {
-		return nil, err
-	}
-	return &solution, nil
-}
+package capsolver
+
+import (
+	"context"
+	"fmt"
+)
+
+// DataDomeSliderTask defines the parameters for a DataDome slider/interstitial task.
+type DataDomeSliderTask struct {
+	// CaptchaURL is the URL to the DataDome captcha; must include t=fe.
+	CaptchaURL string `json:"captchaUrl"`
+	// UserAgent must match the UA used when requesting the target site.
+	UserAgent string `json:"userAgent"`
+	// Proxy is the proxy to solve the task through (required).
+	Proxy Proxy `json:"proxy"`
+}
+
+// TaskType implements Task.
+func (t DataDomeSliderTask) TaskType() string {
+	return "DatadomeSliderTask"
+}
+
+// TaskProxy implements ProxyTask.
+func (t DataDomeSliderTask) TaskProxy() Proxy {
+	return t.Proxy
+}
+
+// WithProxy implements ProxyTask.
+func (t DataDomeSliderTask) WithProxy(p Proxy) Task {
+	t.Proxy = p
+	return t
+}
+
+// SiteURL implements ProxyTask.
+func (t DataDomeSliderTask) SiteURL() string {
+	return t.CaptchaURL
+}
+
+// Validate reports an error if the required proxy is missing or invalid.
+func (t DataDomeSliderTask) Validate() error {
+	if t.Proxy.IsZero() {
+		return fmt.Errorf("%w: DatadomeSliderTask requires a proxy", ErrInvalidProxy)
+	}
+	return t.Proxy.Validate()
+}
+
+// DataDomeSolution represents the solution returned by getTaskResult for DataDome.
+type DataDomeSolution struct {
+	// UserAgent echoes back the User-Agent used during solving.
+	UserAgent string `json:"userAgent"`
+	// Cookie is the DataDome cookie string to use for subsequent requests.
+	Cookie string `json:"cookie"`
+}
+
+// SolveDataDomeSlider submits a DataDomeSliderTask and returns the solution.
+func (s *Session) SolveDataDomeSlider(task DataDomeSliderTask) (*Solved[DataDomeSolution], error) {
+	return s.SolveDataDomeSliderContext(context.Background(), task)
+}
+
+// SolveDataDomeSliderContext is like SolveDataDomeSlider but uses the given context.
+func (s *Session) SolveDataDomeSliderContext(ctx context.Context, task DataDomeSliderTask) (*Solved[DataDomeSolution], error) {
+	return SolveTask[DataDomeSolution](ctx, s, task)
+}
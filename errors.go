@@ -1,86 +1,218 @@
-package capsolver
-
-// ErrorCode represents the CapSolver API error code string.
-type ErrorCode string
-
-const (
-	// ServiceUnavailable indicates the service is temporarily unavailable.
-	// It is possible that the server pressure is high. Please try again later.
-	// If it continues to appear, contact customer service.
-	ServiceUnavailable ErrorCode = "ERROR_SERVICE_UNAVALIABLE"
-
-	// RateLimit indicates the request frequency/rate limit has been exceeded.
-	// This happens when you exceed your service package's request limit.
-	RateLimit ErrorCode = "ERROR_RATE_LIMIT"
-
-	// InvalidTaskData means the submitted task data was invalid.
-	// Please check the details of errorDescription for what was wrong.
-	InvalidTaskData ErrorCode = "ERROR_INVALID_TASK_DATA"
-
-	// BadRequest indicates a general request error.
-	// If this persists, contact customer service.
-	BadRequest ErrorCode = "ERROR_BAD_REQUEST"
-
-	// TaskIDInvalid means the provided task ID does not exist or is invalid.
-	// This may happen if a wrong or expired ID is used.
-	TaskIDInvalid ErrorCode = "ERROR_TASKID_INVALID"
-
-	// TaskTimeout means the task has timed out after waiting too long (120s).
-	// The captcha could not be solved in time.
-	TaskTimeout ErrorCode = "ERROR_TASK_TIMEOUT"
-
-	// SettlementFailed indicates a failure in mission point settlement.
-	// Please check your balance or contact customer service.
-	SettlementFailed ErrorCode = "ERROR_SETTLEMENT_FAILED"
-
-	// KeyDeniedAccess means the provided account key is incorrect.
-	// Check your clientKey and ensure it matches the one from your personal center.
-	KeyDeniedAccess ErrorCode = "ERROR_KEY_DENIED_ACCESS"
-
-	// ZeroBalance indicates the account balance is insufficient.
-	// You need to top up your account to continue usage.
-	ZeroBalance ErrorCode = "ERROR_ZERO_BALANCE"
-
-	// TaskNotSupported means the captcha type is incorrect or not supported.
-	// Please verify the task type you're submitting.
-	TaskNotSupported ErrorCode = "ERROR_TASK_NOT_SUPPORTED"
-
-	// CaptchaUnsolvable means the captcha could not be recognized.
-	// No credits were deducted. Please try again.
-	CaptchaUnsolvable ErrorCode = "ERROR_CAPTCHA_UNSOLVABLE"
-
-	// UnknownQuestion means the submitted question ID was invalid or unknown.
-	// Usually indicates an unprocessable task.
-	UnknownQuestion ErrorCode = "ERROR_UNKNOWN_QUESTION"
-
-	// ProxyBanned means the proxy IP was banned by the target service.
-	// Please use a different proxy.
-	ProxyBanned ErrorCode = "ERROR_PROXY_BANNED"
-
-	// InvalidImage means the image size does not meet the requirements.
-	// Ensure your input image is valid and properly formatted.
-	InvalidImage ErrorCode = "ERROR_INVALID_IMAGE"
-
-	// ParseImageFail means the server failed to parse your image.
-	// Check if your image's BASE64 encoding is correct.
-	ParseImageFail ErrorCode = "ERROR_PARSE_IMAGE_FAIL"
-
-	// IPBanned indicates your IP has been temporarily blocked.
-	// If many errors occur rapidly (e.g., 1000 in 1 minute), a 30-minute block is triggered.
-	IPBanned ErrorCode = "ERROR_IP_BANNED"
-
-	// KeyTempBlocked means your key has been temporarily blocked due to too many errors.
-	// It will be automatically unblocked in 5 minutes. Please try again later.
-	KeyTempBlocked ErrorCode = "ERROR_KEY_TEMP_BLOCKED"
-)
-
-// Error represents a CapSolver API error response.
-type Error struct {
-	ID          int       `json:"errorId"`
-	Code        ErrorCode `json:"errorCode"`
-	Description string    `json:"errorDescription"`
-}
-
-func (e Error) Error() string {
-	return "[capsolver] " + e.Description
-}
+package capsolver
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrorCode represents the CapSolver API error code string.
+type ErrorCode string
+
+const (
+	// ServiceUnavailable indicates the service is temporarily unavailable.
+	// It is possible that the server pressure is high. Please try again later.
+	// If it continues to appear, contact customer service.
+	ServiceUnavailable ErrorCode = "ERROR_SERVICE_UNAVALIABLE"
+
+	// RateLimit indicates the request frequency/rate limit has been exceeded.
+	// This happens when you exceed your service package's request limit.
+	RateLimit ErrorCode = "ERROR_RATE_LIMIT"
+
+	// InvalidTaskData means the submitted task data was invalid.
+	// Please check the details of errorDescription for what was wrong.
+	InvalidTaskData ErrorCode = "ERROR_INVALID_TASK_DATA"
+
+	// BadRequest indicates a general request error.
+	// If this persists, contact customer service.
+	BadRequest ErrorCode = "ERROR_BAD_REQUEST"
+
+	// TaskIDInvalid means the provided task ID does not exist or is invalid.
+	// This may happen if a wrong or expired ID is used.
+	TaskIDInvalid ErrorCode = "ERROR_TASKID_INVALID"
+
+	// TaskTimeout means the task has timed out after waiting too long (120s).
+	// The captcha could not be solved in time.
+	TaskTimeout ErrorCode = "ERROR_TASK_TIMEOUT"
+
+	// SettlementFailed indicates a failure in mission point settlement.
+	// Please check your balance or contact customer service.
+	SettlementFailed ErrorCode = "ERROR_SETTLEMENT_FAILED"
+
+	// KeyDeniedAccess means the provided account key is incorrect.
+	// Check your clientKey and ensure it matches the one from your personal center.
+	KeyDeniedAccess ErrorCode = "ERROR_KEY_DENIED_ACCESS"
+
+	// ZeroBalance indicates the account balance is insufficient.
+	// You need to top up your account to continue usage.
+	ZeroBalance ErrorCode = "ERROR_ZERO_BALANCE"
+
+	// TaskNotSupported means the captcha type is incorrect or not supported.
+	// Please verify the task type you're submitting.
+	TaskNotSupported ErrorCode = "ERROR_TASK_NOT_SUPPORTED"
+
+	// CaptchaUnsolvable means the captcha could not be recognized.
+	// No credits were deducted. Please try again.
+	CaptchaUnsolvable ErrorCode = "ERROR_CAPTCHA_UNSOLVABLE"
+
+	// UnknownQuestion means the submitted question ID was invalid or unknown.
+	// Usually indicates an unprocessable task.
+	UnknownQuestion ErrorCode = "ERROR_UNKNOWN_QUESTION"
+
+	// ProxyBanned means the proxy IP was banned by the target service.
+	// Please use a different proxy.
+	ProxyBanned ErrorCode = "ERROR_PROXY_BANNED"
+
+	// InvalidImage means the image size does not meet the requirements.
+	// Ensure your input image is valid and properly formatted.
+	InvalidImage ErrorCode = "ERROR_INVALID_IMAGE"
+
+	// ParseImageFail means the server failed to parse your image.
+	// Check if your image's BASE64 encoding is correct.
+	ParseImageFail ErrorCode = "ERROR_PARSE_IMAGE_FAIL"
+
+	// IPBanned indicates your IP has been temporarily blocked.
+	// If many errors occur rapidly (e.g., 1000 in 1 minute), a 30-minute block is triggered.
+	IPBanned ErrorCode = "ERROR_IP_BANNED"
+
+	// KeyTempBlocked means your key has been temporarily blocked due to too many errors.
+	// It will be automatically unblocked in 5 minutes. Please try again later.
+	KeyTempBlocked ErrorCode = "ERROR_KEY_TEMP_BLOCKED"
+)
+
+// Error implements the error interface so codes can be used as sentinels with errors.Is.
+func (c ErrorCode) Error() string {
+	return string(c)
+}
+
+// Error represents a CapSolver API error response.
+type Error struct {
+	ID          int       `json:"errorId"`
+	Code        ErrorCode `json:"errorCode"`
+	Description string    `json:"errorDescription"`
+	// TaskID is the ID of the failed task, if one was created.
+	TaskID string `json:"-"`
+	// TaskType is the type of the failed task, if known.
+	TaskType string `json:"-"`
+}
+
+func (e Error) Error() string {
+	msg := "[capsolver] " + string(e.Code)
+	if e.Description != "" {
+		msg += ": " + e.Description
+	}
+	switch {
+	case e.TaskID != "" && e.TaskType != "":
+		msg += " (task " + e.TaskID + ", " + e.TaskType + ")"
+	case e.TaskID != "":
+		msg += " (task " + e.TaskID + ")"
+	case e.TaskType != "":
+		msg += " (" + e.TaskType + ")"
+	}
+	return msg
+}
+
+// Is reports whether target is the ErrorCode of e, so that
+// errors.Is(err, capsolver.ZeroBalance) works on returned errors.
+func (e Error) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && code == e.Code
+}
+
+// IsRetryable reports whether the same task may succeed when submitted again later.
+func (e Error) IsRetryable() bool {
+	switch e.Code {
+	case ServiceUnavailable, RateLimit, TaskTimeout, CaptchaUnsolvable:
+		return true
+	}
+	return false
+}
+
+// IsBillingError reports whether the error is caused by the account balance or settlement.
+func (e Error) IsBillingError() bool {
+	return e.Code == ZeroBalance || e.Code == SettlementFailed
+}
+
+// IsProxyError reports whether the error is caused by the proxy passed with the task.
+func (e Error) IsProxyError() bool {
+	return e.Code == ProxyBanned
+}
+
+// IsInputError reports whether the error is caused by invalid task data.
+// Such tasks fail again unless the input is changed.
+func (e Error) IsInputError() bool {
+	switch e.Code {
+	case InvalidTaskData, BadRequest, TaskIDInvalid, TaskNotSupported, UnknownQuestion, InvalidImage, ParseImageFail:
+		return true
+	}
+	return false
+}
+
+var (
+	// ErrEmptyResponse is returned when the API answers with an empty body.
+	ErrEmptyResponse = errors.New("[capsolver] empty response body")
+	// ErrInvalidResponse is returned when the API answers with a body that is not valid JSON.
+	ErrInvalidResponse = errors.New("[capsolver] invalid response body")
+	// ErrNoTaskID is returned when createTask returns neither a solution nor a task ID.
+	ErrNoTaskID = errors.New("[capsolver] createTask returned neither a solution nor a task ID")
+	// ErrPollTimeout is returned when a task is still not ready after the maximum number of polls.
+	ErrPollTimeout = errors.New("[capsolver] task not ready after maximum number of polls")
+)
+
+// PollTimeoutError is returned when a task is still not ready after the maximum number of polls.
+// The task keeps running on the API side and can be collected later with Session.Wait or Session.WaitPolls.
+type PollTimeoutError struct {
+	// TaskID is the ID of the pending task.
+	TaskID string
+	// Polls is the number of getTaskResult requests sent.
+	Polls int
+	// Elapsed is the time spent polling.
+	Elapsed time.Duration
+}
+
+func (e *PollTimeoutError) Error() string {
+	return fmt.Sprintf("[capsolver] task %s not ready after %d polls (%s)", e.TaskID, e.Polls, e.Elapsed.Round(time.Millisecond))
+}
+
+// Is makes errors.Is(err, ErrPollTimeout) report true.
+func (e *PollTimeoutError) Is(target error) bool {
+	return target == ErrPollTimeout
+}
+
+// maxErrorBody is the number of response body bytes kept in errors.
+const maxErrorBody = 512
+
+// HTTPError is returned when the API answers with a non-2xx status code
+// and no CapSolver error in the body, e.g. a load balancer error page.
+type HTTPError struct {
+	// Endpoint is the API endpoint that was called (e.g. "/createTask").
+	Endpoint string
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Header holds the response headers.
+	Header http.Header
+	// Body is the response body, truncated to 512 bytes.
+	Body string
+}
+
+func newHTTPError(endpoint string, res *http.Response, body []byte) *HTTPError {
+	return &HTTPError{
+		Endpoint:   endpoint,
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+		Body:       truncate(body, maxErrorBody),
+	}
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("[capsolver] %s: unexpected HTTP status %d %s", e.Endpoint, e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// truncate returns at most n bytes of b as a string.
+func truncate(b []byte, n int) string {
+	if len(b) <= n {
+		return string(b)
+	}
+	return string(b[:n]) + "..."
+}
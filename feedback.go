@@ -0,0 +1,24 @@
+package capsolver
+
+import "context"
+
+// Feedback reports whether a solution was accepted by the target site.
+type Feedback struct {
+	// Invalid marks the solution as rejected by the target site.
+	Invalid bool `json:"invalid"`
+	// Code is an optional error code returned by the target site.
+	Code int `json:"code,omitzero"`
+	// Message is an optional reason for the rejection.
+	Message string `json:"message,omitzero"`
+}
+
+// ReportResult tells CapSolver whether the solution of a task was accepted (ok)
+// or rejected by the target site, so the account accuracy statistics stay correct.
+// The task ID is available as Solved.TaskID on every typed solution.
+func (s *Session) ReportResult(ctx context.Context, taskID string, ok bool, reason string) error {
+	_, err := s.do(ctx, endpointFeedbackTask, Payload{
+		TaskID: taskID,
+		Result: &Feedback{Invalid: !ok, Message: reason},
+	})
+	return annotate(err, taskID, "")
+}
@@ -0,0 +1,24 @@
+package capsolver
+
+import "context"
+
+// SolveEvent describes a solve to a SolveHook.
+type SolveEvent struct {
+	// TaskType is the task type sent to the API.
+	TaskType string
+	// Proxy reports whether the task is solved through a proxy.
+	Proxy bool
+	// TaskID is the ID of the task; it is only set when the solve ends,
+	// and also for solves that fail after the task was created.
+	TaskID string
+	// Polls is the number of getTaskResult requests; it is only set when the solve ends.
+	Polls int
+	// Err is the error the solve failed with; it is only set when the solve ends.
+	Err error
+}
+
+// SolveHook is called when a solve starts. The returned context is used for
+// all requests of the solve, so middlewares can attach them to the solve
+// (e.g. as child spans). The returned function is called when the solve ends;
+// for Submit, that is once the task is created.
+type SolveHook func(ctx context.Context, ev SolveEvent) (context.Context, func(ev SolveEvent))
@@ -0,0 +1,51 @@
+package capsolver_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nukilabs/capsolver"
+	"github.com/nukilabs/capsolver/capsolvertest"
+)
+
+func TestSolveHookTaskID(t *testing.T) {
+	tests := []struct {
+		name     string
+		behavior capsolvertest.Behavior
+		opts     []capsolver.Option
+		err      error
+		taskID   string
+		polls    int
+	}{
+		{"solved", capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}, Polls: 1}, nil, nil, "capsolvertest-1", 2},
+		{"poll error", capsolvertest.Behavior{Polls: 1, PollError: capsolver.CaptchaUnsolvable}, nil, capsolver.CaptchaUnsolvable, "capsolvertest-1", 2},
+		{"poll timeout", capsolvertest.Behavior{Polls: 10}, []capsolver.Option{capsolver.WithMaxPolls(3)}, capsolver.ErrPollTimeout, "capsolvertest-1", 3},
+		{"create error", capsolvertest.Behavior{Error: capsolver.InvalidTaskData}, nil, capsolver.InvalidTaskData, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := capsolvertest.NewServer(t)
+			srv.Handle(turnstile.TaskType(), tt.behavior)
+			var events []capsolver.SolveEvent
+			hook := func(ctx context.Context, ev capsolver.SolveEvent) (context.Context, func(capsolver.SolveEvent)) {
+				return ctx, func(ev capsolver.SolveEvent) {
+					events = append(events, ev)
+				}
+			}
+			s := srv.Session(append(tt.opts, capsolver.WithSolveHook(hook))...)
+
+			_, err := s.SolveAntiTurnstile(turnstile)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if len(events) != 1 {
+				t.Fatalf("got %d events, want 1", len(events))
+			}
+			ev := events[0]
+			if ev.TaskType != turnstile.TaskType() || ev.TaskID != tt.taskID || ev.Polls != tt.polls || !errors.Is(ev.Err, tt.err) {
+				t.Errorf("event = %+v, want task %q after %d polls", ev, tt.taskID, tt.polls)
+			}
+		})
+	}
+}
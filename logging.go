@@ -0,0 +1,102 @@
+package capsolver
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+// maxLogString is the length above which strings, such as base64 images, are truncated in logs.
+const maxLogString = 64
+
+// Redacted returns a copy of the payload that is safe to log or store:
+// the client key and proxy passwords are redacted.
+func (p Payload) Redacted() Payload {
+	if p.ClientKey != "" {
+		p.ClientKey = redacted
+	}
+	if p.Task != nil {
+		p.Task = redactTask(p.Task, 0)
+	}
+	return p
+}
+
+// LogValue implements slog.LogValuer. Besides the redactions of Redacted,
+// long values such as base64 images are truncated.
+func (p Payload) LogValue() slog.Value {
+	var attrs []slog.Attr
+	if p.ClientKey != "" {
+		attrs = append(attrs, slog.String("clientKey", redacted))
+	}
+	if p.TaskID != "" {
+		attrs = append(attrs, slog.String("taskId", p.TaskID))
+	}
+	if p.Task != nil {
+		attrs = append(attrs, slog.String("task", string(redactTask(p.Task, maxLogString))))
+	}
+	if p.Result != nil {
+		attrs = append(attrs, slog.Bool("invalid", p.Result.Invalid), slog.String("message", p.Result.Message))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Redacted returns the proxy in the form of String with the password redacted.
+func (p Proxy) Redacted() string {
+	if p.Password != "" {
+		p.Password = redacted
+	}
+	return p.String()
+}
+
+// LogValue implements slog.LogValuer and redacts the password.
+func (p Proxy) LogValue() slog.Value {
+	return slog.StringValue(p.Redacted())
+}
+
+// redactTask encodes the task as JSON with the proxy password redacted and,
+// if limit is positive, strings longer than limit truncated.
+func redactTask(task any, limit int) json.RawMessage {
+	data, ok := task.(json.RawMessage)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(task); err != nil {
+			return json.RawMessage(`null`)
+		}
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return json.RawMessage(`null`)
+	}
+	data, err := json.Marshal(redactValue("", v, limit))
+	if err != nil {
+		return json.RawMessage(`null`)
+	}
+	return data
+}
+
+func redactValue(key string, v any, limit int) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = redactValue(k, item, limit)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(key, item, limit)
+		}
+		return v
+	case string:
+		if key == "proxy" && v != "" {
+			if p, err := ParseProxy(v); err == nil {
+				return p.Redacted()
+			}
+			return redacted
+		}
+		if limit > 0 && len(v) > limit {
+			return fmt.Sprintf("%s...(%d bytes)", v[:limit], len(v))
+		}
+		return v
+	}
+	return v
+}
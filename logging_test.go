@@ -0,0 +1,96 @@
+package capsolver_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/nukilabs/capsolver"
+	"github.com/nukilabs/capsolver/capsolvertest"
+)
+
+const (
+	secretKey      = "secret-client-key"
+	secretPassword = "secret-password"
+)
+
+var secretProxy = capsolver.Proxy{Scheme: capsolver.ProxyHTTP, Host: "127.0.0.1", Port: 8080, User: "user", Password: secretPassword}
+
+// assertNoSecrets fails if out contains the client key, the proxy password or the full image.
+func assertNoSecrets(t *testing.T, out, image string) {
+	t.Helper()
+	for _, secret := range []string{secretKey, secretPassword, image} {
+		if strings.Contains(out, secret) {
+			t.Errorf("output contains %.20q...:\n%s", secret, out)
+		}
+	}
+}
+
+func TestPayloadLogValue(t *testing.T) {
+	longImage := strings.Repeat("aW1hZ2U=", 100)
+	payloads := map[string]capsolver.Payload{
+		"typed proxy": {ClientKey: secretKey, Task: capsolver.ReCaptchaV2Task{WebsiteURL: "https://example.com", WebsiteKey: "key", Proxy: secretProxy}},
+		"typed image": {ClientKey: secretKey, Task: capsolver.ImageToTextTask{Body: longImage, Images: []string{longImage}}},
+		"raw task":    {ClientKey: secretKey, Task: json.RawMessage(`{"type":"ImageToTextTask","proxy":"` + secretProxy.String() + `","body":"` + longImage + `"}`)},
+		"map task":    {ClientKey: secretKey, Task: map[string]any{"type": "ImageToTextTask", "proxy": "not a proxy " + secretPassword, "body": longImage}},
+	}
+	for name, payload := range payloads {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			slog.New(slog.NewJSONHandler(&buf, nil)).Info("request", "payload", payload)
+			assertNoSecrets(t, buf.String(), longImage)
+			if !strings.Contains(buf.String(), "[REDACTED]") {
+				t.Errorf("output does not mark the redactions:\n%s", buf.String())
+			}
+		})
+	}
+}
+
+func TestPayloadRedacted(t *testing.T) {
+	payload := capsolver.Payload{ClientKey: secretKey, Task: capsolver.ReCaptchaV2Task{WebsiteURL: "https://example.com", WebsiteKey: "key", Proxy: secretProxy}}
+	data, err := json.Marshal(payload.Redacted())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNoSecrets(t, string(data), secretKey)
+	if !strings.Contains(string(data), "http:127.0.0.1:8080:user:[REDACTED]") {
+		t.Errorf("redacted payload lost the proxy: %s", data)
+	}
+	// The original payload is left untouched.
+	if payload.ClientKey != secretKey || payload.Task.(capsolver.ReCaptchaV2Task).Proxy != secretProxy {
+		t.Errorf("Redacted modified the payload: %+v", payload)
+	}
+}
+
+func TestProxyLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("proxy", "proxy", secretProxy)
+	assertNoSecrets(t, buf.String(), secretKey)
+	if !strings.Contains(buf.String(), "http:127.0.0.1:8080:user:[REDACTED]") {
+		t.Errorf("output = %s, want the redacted proxy", buf.String())
+	}
+}
+
+func TestSessionLogsNoSecrets(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	task := capsolver.ReCaptchaV2Task{WebsiteURL: "https://example.com", WebsiteKey: "key", Proxy: secretProxy}
+	srv.Handle(task.TaskType(), capsolvertest.Behavior{Error: capsolver.ProxyBanned})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	s := capsolver.New(secretKey,
+		capsolver.WithBaseURL(srv.URL),
+		capsolver.WithHTTPClient(srv.Client()),
+		capsolver.WithLogger(logger),
+		capsolver.WithMiddleware(capsolver.LoggingMiddleware(logger)),
+	)
+	if _, err := s.SolveReCaptchaV2(task); err == nil {
+		t.Fatal("solve succeeded")
+	}
+	if buf.Len() == 0 {
+		t.Fatal("nothing logged")
+	}
+	assertNoSecrets(t, buf.String(), secretKey)
+}
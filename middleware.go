@@ -0,0 +1,187 @@
+package capsolver
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Handler sends a request to a CapSolver endpoint (e.g. "/createTask").
+// The payload already carries the client key and app ID.
+type Handler func(ctx context.Context, endpoint string, payload Payload) (*Result, error)
+
+// Middleware wraps a Handler, e.g. to log, trace or fake requests.
+type Middleware func(next Handler) Handler
+
+// Use appends middlewares to the request chain. The first middleware
+// registered is the outermost one. Use must not be called concurrently
+// with requests; prefer WithMiddleware when creating the session.
+func (s *Session) Use(mw ...Middleware) {
+	s.middlewares = append(s.middlewares, mw...)
+	s.buildHandler()
+}
+
+func (s *Session) buildHandler() {
+	h := Handler(s.send)
+	for i := len(s.middlewares) - 1; i >= 0; i-- {
+		h = s.middlewares[i](h)
+	}
+	s.handler = h
+}
+
+const redacted = "[REDACTED]"
+
+// LoggingMiddleware logs every request at debug level and failed requests at warn level.
+// The payload is logged through Payload.LogValue and thus redacted.
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, endpoint string, payload Payload) (*Result, error) {
+			start := time.Now()
+			res, err := next(ctx, endpoint, payload)
+			attrs := []slog.Attr{
+				slog.String("endpoint", endpoint),
+				slog.Duration("duration", time.Since(start)),
+				slog.Any("payload", payload),
+			}
+			if res != nil {
+				attrs = append(attrs, slog.String("taskId", res.TaskId), slog.String("status", string(res.Status)))
+				if res.Error.ID != 0 {
+					attrs = append(attrs, slog.String("errorCode", string(res.Error.Code)))
+				}
+			}
+			if err != nil || (res != nil && res.Error.ID != 0) {
+				if err != nil {
+					attrs = append(attrs, slog.Any("error", err))
+				}
+				logger.LogAttrs(ctx, slog.LevelWarn, "capsolver request failed", attrs...)
+			} else {
+				logger.LogAttrs(ctx, slog.LevelDebug, "capsolver request", attrs...)
+			}
+			return res, err
+		}
+	}
+}
+
+// EndpointMetrics holds the request counters of an endpoint.
+type EndpointMetrics struct {
+	// Requests is the number of requests sent.
+	Requests int64
+	// Errors is the number of requests that failed, including API errors.
+	Errors int64
+	// ErrorCodes counts the API errors by code.
+	ErrorCodes map[ErrorCode]int64
+	// Latency is the total time spent in requests.
+	Latency time.Duration
+}
+
+// Metrics collects request counters per endpoint. Register it with MetricsMiddleware.
+type Metrics struct {
+	mu        sync.Mutex
+	endpoints map[string]*EndpointMetrics
+}
+
+// NewMetrics creates an empty Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{endpoints: make(map[string]*EndpointMetrics)}
+}
+
+// Snapshot returns a copy of the counters, keyed by endpoint.
+func (m *Metrics) Snapshot() map[string]EndpointMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot := make(map[string]EndpointMetrics, len(m.endpoints))
+	for endpoint, em := range m.endpoints {
+		cp := *em
+		cp.ErrorCodes = make(map[ErrorCode]int64, len(em.ErrorCodes))
+		for code, n := range em.ErrorCodes {
+			cp.ErrorCodes[code] = n
+		}
+		snapshot[endpoint] = cp
+	}
+	return snapshot
+}
+
+func (m *Metrics) observe(endpoint string, d time.Duration, res *Result, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	em, ok := m.endpoints[endpoint]
+	if !ok {
+		em = &EndpointMetrics{ErrorCodes: make(map[ErrorCode]int64)}
+		m.endpoints[endpoint] = em
+	}
+	em.Requests++
+	em.Latency += d
+	if res != nil && res.Error.ID != 0 {
+		em.Errors++
+		em.ErrorCodes[res.Error.Code]++
+	} else if err != nil {
+		em.Errors++
+	}
+}
+
+// MetricsMiddleware records every request in m.
+func MetricsMiddleware(m *Metrics) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, endpoint string, payload Payload) (*Result, error) {
+			start := time.Now()
+			res, err := next(ctx, endpoint, payload)
+			m.observe(endpoint, time.Since(start), res, err)
+			return res, err
+		}
+	}
+}
+
+// Exchange is a request recorded by a Recorder.
+type Exchange struct {
+	// Endpoint is the endpoint that was called.
+	Endpoint string
+	// Payload is the request payload with the client key redacted.
+	Payload Payload
+	// Result is the API response, nil if Err is set.
+	Result *Result
+	// Err is the transport or decoding error, if any.
+	Err error
+	// Duration is the time the request took.
+	Duration time.Duration
+}
+
+// Recorder records the requests and responses passing through its middleware.
+type Recorder struct {
+	mu        sync.Mutex
+	exchanges []Exchange
+}
+
+// Middleware returns the middleware that records into r.
+func (r *Recorder) Middleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, endpoint string, payload Payload) (*Result, error) {
+			start := time.Now()
+			res, err := next(ctx, endpoint, payload)
+			r.mu.Lock()
+			r.exchanges = append(r.exchanges, Exchange{
+				Endpoint: endpoint,
+				Payload:  payload.Redacted(),
+				Result:   res,
+				Err:      err,
+				Duration: time.Since(start),
+			})
+			r.mu.Unlock()
+			return res, err
+		}
+	}
+}
+
+// Exchanges returns the recorded exchanges in order.
+func (r *Recorder) Exchanges() []Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Exchange(nil), r.exchanges...)
+}
+
+// Reset drops the recorded exchanges.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.exchanges = nil
+}
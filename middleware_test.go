@@ -0,0 +1,148 @@
+package capsolver_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/nukilabs/capsolver"
+	"github.com/nukilabs/capsolver/capsolvertest"
+)
+
+func TestUseOrder(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(image.TaskType(), capsolvertest.Behavior{Solution: capsolver.ImageToTextSolution{Text: "abc"}, Sync: true})
+
+	var calls []string
+	trace := func(name string) capsolver.Middleware {
+		return func(next capsolver.Handler) capsolver.Handler {
+			return func(ctx context.Context, endpoint string, payload capsolver.Payload) (*capsolver.Result, error) {
+				calls = append(calls, name+" in")
+				res, err := next(ctx, endpoint, payload)
+				calls = append(calls, name+" out")
+				return res, err
+			}
+		}
+	}
+	s := srv.Session(capsolver.WithMiddleware(trace("a")))
+	s.Use(trace("b"), trace("c"))
+	if _, err := capsolver.SolveTask[capsolver.ImageToTextSolution](context.Background(), s, image); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"a in", "b in", "c in", "c out", "b out", "a out"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(image.TaskType(), capsolvertest.Behavior{Solution: capsolver.ImageToTextSolution{Text: "abc"}, Sync: true})
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Error: capsolver.InvalidTaskData})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	s := srv.Session(capsolver.WithMiddleware(capsolver.LoggingMiddleware(logger)))
+	ctx := context.Background()
+	if _, err := capsolver.SolveTask[capsolver.ImageToTextSolution](ctx, s, image); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SolveAntiTurnstileContext(ctx, turnstile); err == nil {
+		t.Fatal("turnstile solve succeeded")
+	}
+
+	var records []map[string]any
+	for line := range strings.Lines(buf.String()) {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2:\n%s", len(records), buf.String())
+	}
+	for i, want := range []struct{ level, msg, errorCode string }{
+		{"DEBUG", "capsolver request", ""},
+		{"WARN", "capsolver request failed", string(capsolver.InvalidTaskData)},
+	} {
+		r := records[i]
+		if r["level"] != want.level || r["msg"] != want.msg || r["endpoint"] != "/createTask" {
+			t.Errorf("record %d = %v, want %s %q for /createTask", i, r, want.level, want.msg)
+		}
+		if code, _ := r["errorCode"].(string); code != want.errorCode {
+			t.Errorf("record %d errorCode = %q, want %q", i, code, want.errorCode)
+		}
+		if payload, _ := r["payload"].(map[string]any); payload["clientKey"] != "[REDACTED]" {
+			t.Errorf("record %d payload = %v, want the client key redacted", i, r["payload"])
+		}
+	}
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}, Polls: 2})
+	srv.Handle(image.TaskType(), capsolvertest.Behavior{Error: capsolver.InvalidTaskData})
+
+	m := capsolver.NewMetrics()
+	s := srv.Session(capsolver.WithMiddleware(capsolver.MetricsMiddleware(m)))
+	ctx := context.Background()
+	if _, err := s.SolveAntiTurnstileContext(ctx, turnstile); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := capsolver.SolveTask[capsolver.ImageToTextSolution](ctx, s, image); err == nil {
+		t.Fatal("image solve succeeded")
+	}
+
+	snapshot := m.Snapshot()
+	create, poll := snapshot["/createTask"], snapshot["/getTaskResult"]
+	if create.Requests != 2 || create.Errors != 1 || create.ErrorCodes[capsolver.InvalidTaskData] != 1 || create.Latency <= 0 {
+		t.Errorf("/createTask = %+v, want 2 requests with 1 %s", create, capsolver.InvalidTaskData)
+	}
+	if poll.Requests != 3 || poll.Errors != 0 || len(poll.ErrorCodes) != 0 {
+		t.Errorf("/getTaskResult = %+v, want 3 requests without errors", poll)
+	}
+
+	// The snapshot is a copy.
+	create.ErrorCodes[capsolver.InvalidTaskData] = 100
+	if n := m.Snapshot()["/createTask"].ErrorCodes[capsolver.InvalidTaskData]; n != 1 {
+		t.Errorf("ErrorCodes changed through a snapshot to %d", n)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}, Polls: 1})
+
+	var rec capsolver.Recorder
+	s := srv.Session(capsolver.WithMiddleware(rec.Middleware()))
+	sol, err := s.SolveAntiTurnstile(turnstile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exchanges := rec.Exchanges()
+	endpoints := []string{"/createTask", "/getTaskResult", "/getTaskResult"}
+	if len(exchanges) != len(endpoints) {
+		t.Fatalf("got %d exchanges, want %d", len(exchanges), len(endpoints))
+	}
+	for i, ex := range exchanges {
+		if ex.Endpoint != endpoints[i] || ex.Err != nil || ex.Result == nil {
+			t.Errorf("exchange %d = %+v, want a result from %s", i, ex, endpoints[i])
+		}
+		if ex.Payload.ClientKey != "[REDACTED]" {
+			t.Errorf("exchange %d client key = %q, want it redacted", i, ex.Payload.ClientKey)
+		}
+	}
+	if exchanges[2].Payload.TaskID != sol.TaskID || exchanges[2].Result.Status != capsolver.StatusReady {
+		t.Errorf("last exchange = %+v, want the ready result of %s", exchanges[2], sol.TaskID)
+	}
+
+	rec.Reset()
+	if n := len(rec.Exchanges()); n != 0 {
+		t.Errorf("got %d exchanges after Reset, want 0", n)
+	}
+}
@@ -1,36 +1,58 @@
-package capsolver
-
-// MtCaptchaTask defines an MTCaptcha token solve task.
-type MtCaptchaTask struct {
-	Type string `json:"type"`
-	// WebsiteURL is the URL of the page protected by MtCaptcha.
-	WebsiteURL string `json:"websiteURL"`
-	// WebsiteKey is the public domain key for MtCaptcha (e.g. "MTPublic-xxx").
-	WebsiteKey string `json:"websiteKey"`
-	// Proxy specifies your proxy (e.g., "http://ip:port:user:pass").
-	Proxy string `json:"proxy,omitzero"`
-}
-
-// MtCaptchaSolution represents the result returned by getTaskResult for MTCaptcha.
-type MtCaptchaSolution struct {
-	// Token is the captcha token to submit to the target site.
-	Token string `json:"token"`
-}
-
-// SolveMtCaptcha submits an MtCaptchaTask and returns the solution.
-func (s *Session) SolveMtCaptcha(task MtCaptchaTask) (*MtCaptchaSolution, error) {
-	if task.Proxy != "" {
-		task.Type = "MtCaptchaTask"
-	} else {
-		task.Type = "MtCaptchaTaskProxyLess"
-	}
-	res, err := s.Solve(task)
-	if err != nil {
-		return nil, err
-	}
-	var solution MtCaptchaSolution
-	if err := res.Unmarshal(&solution); err != nil {
-		return nil, err
-	}
-	return &solution, nil
-}
+package capsolver
+
+import "context"
+
+// MtCaptchaTask defines an MTCaptcha token solve task.
+type MtCaptchaTask struct {
+	// WebsiteURL is the URL of the page protected by MtCaptcha.
+	WebsiteURL string `json:"websiteURL"`
+	// WebsiteKey is the public domain key for MtCaptcha (e.g. "MTPublic-xxx").
+	WebsiteKey string `json:"websiteKey"`
+	// Proxy is the proxy to solve the task through; leave empty for the proxy-less variant.
+	Proxy Proxy `json:"proxy,omitzero"`
+}
+
+// TaskType implements Task.
+func (t MtCaptchaTask) TaskType() string {
+	if !t.Proxy.IsZero() {
+		return "MtCaptchaTask"
+	}
+	return "MtCaptchaTaskProxyLess"
+}
+
+// TokenKey implements TokenTask.
+func (t MtCaptchaTask) TokenKey() TokenKey {
+	return TokenKey{Type: t.TaskType(), WebsiteURL: t.WebsiteURL, WebsiteKey: t.WebsiteKey, Action: ""}
+}
+
+// TaskProxy implements ProxyTask.
+func (t MtCaptchaTask) TaskProxy() Proxy {
+	return t.Proxy
+}
+
+// WithProxy implements ProxyTask.
+func (t MtCaptchaTask) WithProxy(p Proxy) Task {
+	t.Proxy = p
+	return t
+}
+
+// SiteURL implements ProxyTask.
+func (t MtCaptchaTask) SiteURL() string {
+	return t.WebsiteURL
+}
+
+// MtCaptchaSolution represents the result returned by getTaskResult for MTCaptcha.
+type MtCaptchaSolution struct {
+	// Token is the captcha token to submit to the target site.
+	Token string `json:"token"`
+}
+
+// SolveMtCaptcha submits an MtCaptchaTask and returns the solution.
+func (s *Session) SolveMtCaptcha(task MtCaptchaTask) (*Solved[MtCaptchaSolution], error) {
+	return s.SolveMtCaptchaContext(context.Background(), task)
+}
+
+// SolveMtCaptchaContext is like SolveMtCaptcha but uses the given context.
+func (s *Session) SolveMtCaptchaContext(ctx context.Context, task MtCaptchaTask) (*Solved[MtCaptchaSolution], error) {
+	return SolveTask[MtCaptchaSolution](ctx, s, task)
+}
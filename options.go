@@ -0,0 +1,139 @@
+package capsolver
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures a Session.
+type Option func(*Session)
+
+// WithBaseURL sets the API base URL (e.g. a regional mirror or a local fake server).
+func WithBaseURL(url string) Option {
+	return func(s *Session) {
+		s.baseURL = strings.TrimRight(url, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for API requests.
+// A nil client keeps the default client.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Session) {
+		if client != nil {
+			s.client = client
+		}
+	}
+}
+
+// WithAppID sets the app ID sent with every request.
+func WithAppID(id string) Option {
+	return func(s *Session) {
+		s.appID = id
+	}
+}
+
+// WithPollInterval sets the delay between getTaskResult requests.
+func WithPollInterval(d time.Duration) Option {
+	return func(s *Session) {
+		s.pollInterval = d
+	}
+}
+
+// WithMaxPolls sets the maximum number of getTaskResult requests per task.
+func WithMaxPolls(n int) Option {
+	return func(s *Session) {
+		s.maxPolls = n
+	}
+}
+
+// WithInitialDelay sets the delay between createTask and the first getTaskResult request.
+func WithInitialDelay(d time.Duration) Option {
+	return func(s *Session) {
+		s.initialDelay = d
+	}
+}
+
+// WithRetryPolicy sets the retry policy for transient API failures.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(s *Session) {
+		s.retry = p
+	}
+}
+
+// WithBalanceHook registers fn to be called when GetBalance reports a balance
+// below threshold, or when any request fails with ZeroBalance.
+func WithBalanceHook(threshold float64, fn BalanceHook) Option {
+	return func(s *Session) {
+		s.balanceThreshold = threshold
+		s.balanceHook = fn
+	}
+}
+
+// WithProxyPool fills in the proxy of proxy-capable tasks that have none
+// and retries them on another proxy when the API reports ProxyBanned.
+func WithProxyPool(pool *ProxyPool) Option {
+	return func(s *Session) {
+		s.proxies = pool
+	}
+}
+
+// WithPollRate caps the getTaskResult requests of all pending tasks of the
+// session to rps requests per second. Zero (the default) disables the cap.
+func WithPollRate(rps float64) Option {
+	return func(s *Session) {
+		s.poller.interval = 0
+		if rps > 0 {
+			s.poller.interval = time.Duration(float64(time.Second) / rps)
+		}
+	}
+}
+
+// WithCreateTaskRate limits createTask requests to rps requests per second
+// with bursts of up to burst requests. The rate is lowered automatically
+// when the API reports RateLimit.
+func WithCreateTaskRate(rps float64, burst int) Option {
+	return withRateLimit(endpointCreateTask, rps, burst)
+}
+
+// WithGetTaskResultRate limits getTaskResult requests like WithCreateTaskRate.
+func WithGetTaskResultRate(rps float64, burst int) Option {
+	return withRateLimit(endpointGetTaskResult, rps, burst)
+}
+
+func withRateLimit(endpoint string, rps float64, burst int) Option {
+	return func(s *Session) {
+		if rps <= 0 {
+			delete(s.limiters, endpoint)
+			return
+		}
+		s.limiters[endpoint] = newRateLimiter(rps, burst)
+	}
+}
+
+// WithMiddleware appends middlewares to the request chain, see Session.Use.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(s *Session) {
+		s.middlewares = append(s.middlewares, mw...)
+	}
+}
+
+// WithLogger sets the logger for task lifecycle events: creation, polls,
+// status changes, retries, errors and completion. Secrets are redacted.
+// A nil logger keeps the default logger, which discards everything.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Session) {
+		if logger != nil {
+			s.logger = logger
+		}
+	}
+}
+
+// WithSolveHook registers a hook that observes every solve, see SolveHook.
+// It replaces any previously registered hook.
+func WithSolveHook(hook SolveHook) Option {
+	return func(s *Session) {
+		s.solveHook = hook
+	}
+}
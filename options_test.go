@@ -0,0 +1,32 @@
+package capsolver_test
+
+import (
+	"testing"
+
+	"github.com/nukilabs/capsolver"
+	"github.com/nukilabs/capsolver/capsolvertest"
+)
+
+func TestWithHTTPClientNil(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle("ImageToTextTask", capsolvertest.Behavior{Solution: capsolver.ImageToTextSolution{Text: "abc"}, Sync: true})
+
+	s := srv.Session(capsolver.WithHTTPClient(nil))
+	sol, err := s.SolveImageToText(capsolver.ImageToTextTask{Body: "aW1hZ2U="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sol.Solution.Text != "abc" {
+		t.Errorf("text = %q, want %q", sol.Solution.Text, "abc")
+	}
+}
+
+func TestWithLoggerNil(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle("ImageToTextTask", capsolvertest.Behavior{Solution: capsolver.ImageToTextSolution{Text: "abc"}, Sync: true})
+
+	s := srv.Session(capsolver.WithLogger(nil))
+	if _, err := s.SolveImageToText(capsolver.ImageToTextTask{Body: "aW1hZ2U="}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,180 @@
+// Package otel instruments a capsolver.Session with OpenTelemetry traces and metrics.
+//
+// Every solve gets a span with a child span per createTask and getTaskResult
+// request. Solve latency, poll counts and errors are recorded as metrics
+// labelled by task type, proxy usage and error code; export them to
+// Prometheus with the OpenTelemetry Prometheus exporter.
+//
+// It lives in its own module so that the core SDK does not depend on OpenTelemetry.
+package otel
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nukilabs/capsolver"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const scope = "github.com/nukilabs/capsolver/otel"
+
+// Attribute keys used on spans and metrics.
+const (
+	TaskTypeKey  = attribute.Key("capsolver.task.type")
+	TaskIDKey    = attribute.Key("capsolver.task.id")
+	ProxyKey     = attribute.Key("capsolver.proxy")
+	ErrorCodeKey = attribute.Key("capsolver.error.code")
+	EndpointKey  = attribute.Key("capsolver.endpoint")
+	PollsKey     = attribute.Key("capsolver.polls")
+)
+
+// Option configures the instrumentation.
+type Option func(*config)
+
+type config struct {
+	tp trace.TracerProvider
+	mp metric.MeterProvider
+}
+
+// WithTracerProvider sets the tracer provider (the global one by default).
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tp = tp
+	}
+}
+
+// WithMeterProvider sets the meter provider (the global one by default).
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.mp = mp
+	}
+}
+
+// Instrumentation holds the tracer and instruments of a session.
+type Instrumentation struct {
+	tracer trace.Tracer
+
+	solveDuration   metric.Float64Histogram
+	solves          metric.Int64Counter
+	polls           metric.Int64Histogram
+	requestDuration metric.Float64Histogram
+	requests        metric.Int64Counter
+}
+
+// New creates the instrumentation. Pass Option() to capsolver.New to enable it.
+func New(opts ...Option) (*Instrumentation, error) {
+	c := config{tp: otel.GetTracerProvider(), mp: otel.GetMeterProvider()}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	meter := c.mp.Meter(scope)
+	inst := &Instrumentation{tracer: c.tp.Tracer(scope)}
+	var err, e error
+	inst.solveDuration, e = meter.Float64Histogram("capsolver.solve.duration",
+		metric.WithDescription("Duration of solves from createTask to the solution."), metric.WithUnit("s"))
+	err = errors.Join(err, e)
+	inst.solves, e = meter.Int64Counter("capsolver.solves",
+		metric.WithDescription("Number of solves, labelled by error code when they fail."))
+	err = errors.Join(err, e)
+	inst.polls, e = meter.Int64Histogram("capsolver.solve.polls",
+		metric.WithDescription("Number of getTaskResult requests per solve."))
+	err = errors.Join(err, e)
+	inst.requestDuration, e = meter.Float64Histogram("capsolver.request.duration",
+		metric.WithDescription("Duration of API requests."), metric.WithUnit("s"))
+	err = errors.Join(err, e)
+	inst.requests, e = meter.Int64Counter("capsolver.requests",
+		metric.WithDescription("Number of API requests, labelled by error code when they fail."))
+	err = errors.Join(err, e)
+	if err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+// Option returns the capsolver.Option that installs the solve hook and request middleware.
+func (inst *Instrumentation) Option() capsolver.Option {
+	return func(s *capsolver.Session) {
+		capsolver.WithSolveHook(inst.solveHook)(s)
+		capsolver.WithMiddleware(inst.middleware)(s)
+	}
+}
+
+func (inst *Instrumentation) solveHook(ctx context.Context, ev capsolver.SolveEvent) (context.Context, func(capsolver.SolveEvent)) {
+	start := time.Now()
+	ctx, span := inst.tracer.Start(ctx, "capsolver.solve "+ev.TaskType,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(TaskTypeKey.String(ev.TaskType), ProxyKey.Bool(ev.Proxy)))
+	return ctx, func(ev capsolver.SolveEvent) {
+		attrs := []attribute.KeyValue{TaskTypeKey.String(ev.TaskType), ProxyKey.Bool(ev.Proxy)}
+		if code := errorCode(ev.Err); code != "" {
+			attrs = append(attrs, ErrorCodeKey.String(code))
+		}
+		set := metric.WithAttributes(attrs...)
+		inst.solveDuration.Record(ctx, time.Since(start).Seconds(), set)
+		inst.solves.Add(ctx, 1, set)
+		inst.polls.Record(ctx, int64(ev.Polls), metric.WithAttributes(TaskTypeKey.String(ev.TaskType), ProxyKey.Bool(ev.Proxy)))
+
+		span.SetAttributes(TaskIDKey.String(ev.TaskID), PollsKey.Int(ev.Polls))
+		if ev.Err != nil {
+			span.RecordError(ev.Err)
+			span.SetStatus(codes.Error, ev.Err.Error())
+		}
+		span.End()
+	}
+}
+
+func (inst *Instrumentation) middleware(next capsolver.Handler) capsolver.Handler {
+	return func(ctx context.Context, endpoint string, payload capsolver.Payload) (*capsolver.Result, error) {
+		start := time.Now()
+		ctx, span := inst.tracer.Start(ctx, "capsolver "+endpoint,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(EndpointKey.String(endpoint)))
+		defer span.End()
+		if payload.TaskID != "" {
+			span.SetAttributes(TaskIDKey.String(payload.TaskID))
+		}
+
+		res, err := next(ctx, endpoint, payload)
+
+		attrs := []attribute.KeyValue{EndpointKey.String(endpoint)}
+		switch {
+		case err != nil:
+			if code := errorCode(err); code != "" {
+				attrs = append(attrs, ErrorCodeKey.String(code))
+			}
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		case res.Error.ID != 0:
+			attrs = append(attrs, ErrorCodeKey.String(string(res.Error.Code)))
+			span.SetStatus(codes.Error, string(res.Error.Code))
+		}
+		span.SetAttributes(attrs...)
+		set := metric.WithAttributes(attrs...)
+		inst.requestDuration.Record(ctx, time.Since(start).Seconds(), set)
+		inst.requests.Add(ctx, 1, set)
+		return res, err
+	}
+}
+
+// errorCode returns the CapSolver error code of err, "timeout" for poll
+// timeouts, "canceled" when the context is done, "error" for other failures
+// and "" for nil.
+func errorCode(err error) string {
+	var apiErr capsolver.Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.As(err, &apiErr):
+		return string(apiErr.Code)
+	case errors.Is(err, capsolver.ErrPollTimeout):
+		return "timeout"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "canceled"
+	}
+	return "error"
+}
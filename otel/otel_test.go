@@ -0,0 +1,136 @@
+package otel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nukilabs/capsolver"
+	"github.com/nukilabs/capsolver/capsolvertest"
+	capsolverotel "github.com/nukilabs/capsolver/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var turnstile = capsolver.AntiTurnstileTask{WebsiteURL: "https://example.com", WebsiteKey: "key"}
+
+// session returns a session instrumented with an in-memory span recorder and a manual metric reader.
+func session(t *testing.T, srv *capsolvertest.Server, opts ...capsolver.Option) (*capsolver.Session, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	inst, err := capsolverotel.New(
+		capsolverotel.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		capsolverotel.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv.Session(append(opts, inst.Option())...), spans, reader
+}
+
+// sums returns the data points of the counter named name.
+func sums(t *testing.T, reader *sdkmetric.ManualReader, name string) []metricdata.DataPoint[int64] {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m.Data.(metricdata.Sum[int64]).DataPoints
+			}
+		}
+	}
+	t.Fatalf("no metric %q", name)
+	return nil
+}
+
+func TestSolveSpans(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}, Polls: 1})
+	s, spans, _ := session(t, srv)
+
+	sol, err := s.SolveAntiTurnstile(turnstile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ended := spans.Ended()
+	if len(ended) != 4 {
+		t.Fatalf("got %d spans, want solve, createTask and 2 getTaskResult", len(ended))
+	}
+	solve := ended[len(ended)-1]
+	if want := "capsolver.solve " + turnstile.TaskType(); solve.Name() != want {
+		t.Fatalf("last span = %q, want %q", solve.Name(), want)
+	}
+	if solve.Parent().IsValid() {
+		t.Error("solve span has a parent")
+	}
+	attrs := attribute.NewSet(solve.Attributes()...)
+	for key, want := range map[attribute.Key]attribute.Value{
+		capsolverotel.TaskTypeKey: attribute.StringValue(turnstile.TaskType()),
+		capsolverotel.TaskIDKey:   attribute.StringValue(sol.TaskID),
+		capsolverotel.ProxyKey:    attribute.BoolValue(false),
+		capsolverotel.PollsKey:    attribute.IntValue(2),
+	} {
+		if got, _ := attrs.Value(key); got != want {
+			t.Errorf("solve span %s = %v, want %v", key, got.Emit(), want.Emit())
+		}
+	}
+
+	names := []string{"capsolver /createTask", "capsolver /getTaskResult", "capsolver /getTaskResult"}
+	for i, span := range ended[:3] {
+		if span.Name() != names[i] {
+			t.Errorf("span %d = %q, want %q", i, span.Name(), names[i])
+		}
+		if span.Parent().SpanID() != solve.SpanContext().SpanID() {
+			t.Errorf("span %q is not a child of the solve span", span.Name())
+		}
+	}
+}
+
+func TestSolveMetrics(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}, Sync: true})
+	proxied := capsolver.ReCaptchaV2Task{WebsiteURL: "https://example.com", WebsiteKey: "key", Proxy: capsolver.MustParseProxy("http://127.0.0.1:8080")}
+	srv.Handle(proxied.TaskType(), capsolvertest.Behavior{Error: capsolver.InvalidTaskData})
+	s, spans, reader := session(t, srv, capsolver.WithRetryPolicy(capsolver.NoRetry))
+
+	if _, err := s.SolveAntiTurnstile(turnstile); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SolveReCaptchaV2(proxied); err == nil {
+		t.Fatal("proxied solve succeeded")
+	}
+
+	want := map[attribute.Set]int64{
+		attribute.NewSet(capsolverotel.TaskTypeKey.String(turnstile.TaskType()), capsolverotel.ProxyKey.Bool(false)): 1,
+		attribute.NewSet(capsolverotel.TaskTypeKey.String(proxied.TaskType()), capsolverotel.ProxyKey.Bool(true),
+			capsolverotel.ErrorCodeKey.String(string(capsolver.InvalidTaskData))): 1,
+	}
+	points := sums(t, reader, "capsolver.solves")
+	if len(points) != len(want) {
+		t.Errorf("got %d capsolver.solves series, want %d", len(points), len(want))
+	}
+	for _, p := range points {
+		if want[p.Attributes] != p.Value {
+			t.Errorf("capsolver.solves{%s} = %d, want %d", p.Attributes.Encoded(attribute.DefaultEncoder()), p.Value, want[p.Attributes])
+		}
+	}
+
+	failed := spans.Ended()[len(spans.Ended())-1]
+	if failed.Status().Code != codes.Error {
+		t.Errorf("failed solve span status = %v, want %v", failed.Status().Code, codes.Error)
+	}
+	for _, p := range sums(t, reader, "capsolver.requests") {
+		code, ok := p.Attributes.Value(capsolverotel.ErrorCodeKey)
+		if ok && code.AsString() != string(capsolver.InvalidTaskData) {
+			t.Errorf("capsolver.requests error code = %q, want %q", code.AsString(), capsolver.InvalidTaskData)
+		}
+	}
+}
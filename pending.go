@@ -0,0 +1,267 @@
+package capsolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Pending is a created task whose solution has not been collected yet.
+// It can be serialized with json.Marshal and resumed in another process with Resume.
+type Pending[S any] struct {
+	s       *Session
+	id      string
+	typ     string
+	created time.Time
+
+	mu      sync.Mutex
+	run     *pendingRun // the background polling; nil if not polling
+	waiters int         // Wait calls in progress
+	watched bool        // Done was called
+	polls   int
+
+	once sync.Once
+	done chan struct{}
+	sol  *Solved[S]
+	err  error
+}
+
+// pendingRun is a background polling of a Pending.
+type pendingRun struct {
+	stop context.CancelFunc
+	// timeout is closed when the polling ends with err, a *PollTimeoutError,
+	// which is reported to the waiters without completing the task.
+	timeout chan struct{}
+	err     error
+}
+
+// pendingState is the serialized form of a Pending.
+type pendingState struct {
+	TaskID    string          `json:"taskId,omitzero"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Solution  json.RawMessage `json:"solution,omitzero"`
+}
+
+// Submit creates the task and returns as soon as createTask succeeds,
+// without waiting for the solution.
+// Proxies of the session proxy pool are used and rotated like in a solve.
+func Submit[S any](ctx context.Context, s *Session, task Task) (*Pending[S], error) {
+	res, tr, err := s.run(ctx, task, false)
+	if err != nil {
+		return nil, err
+	}
+	p := newPending[S](s, res.TaskId, tr.typ, tr.created)
+	if res.ready() {
+		p.finish(res)
+	}
+	return p, nil
+}
+
+// Resume restores a Pending serialized with json.Marshal and attaches it to s.
+func Resume[S any](s *Session, data []byte) (*Pending[S], error) {
+	var state pendingState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	p := newPending[S](s, state.TaskID, state.Type, state.CreatedAt)
+	if len(state.Solution) > 0 {
+		p.finish(&Result{Status: StatusReady, TaskId: state.TaskID, Solution: state.Solution})
+	} else if state.TaskID == "" {
+		return nil, ErrNoTaskID
+	}
+	return p, nil
+}
+
+func newPending[S any](s *Session, id, typ string, created time.Time) *Pending[S] {
+	return &Pending[S]{s: s, id: id, typ: typ, created: created, done: make(chan struct{})}
+}
+
+// TaskID returns the ID of the task.
+func (p *Pending[S]) TaskID() string {
+	return p.id
+}
+
+// Done returns a channel that is closed once the task is solved or has failed.
+// Calling Done starts polling the task in the background until it completes or Cancel is called.
+func (p *Pending[S]) Done() <-chan struct{} {
+	p.mu.Lock()
+	p.watched = true
+	p.start()
+	p.mu.Unlock()
+	return p.done
+}
+
+// Wait blocks until the solution is ready, the task fails or ctx is done.
+// When ctx is done and no other Wait or Done is interested in the task,
+// the background polling stops; a later Wait or Done resumes it.
+// If the task is still not ready after the maximum number of polls (see WithMaxPolls),
+// Wait returns a *PollTimeoutError and a later Wait polls again.
+func (p *Pending[S]) Wait(ctx context.Context) (*Solved[S], error) {
+	p.mu.Lock()
+	p.waiters++
+	run := p.start()
+	var timeout chan struct{}
+	if run != nil {
+		timeout = run.timeout
+	}
+	p.mu.Unlock()
+	select {
+	case <-p.done:
+		p.mu.Lock()
+		p.waiters--
+		p.mu.Unlock()
+		return p.sol, p.err
+	case <-timeout:
+		p.mu.Lock()
+		p.waiters--
+		p.mu.Unlock()
+		return nil, run.err
+	case <-ctx.Done():
+		p.mu.Lock()
+		p.waiters--
+		if p.waiters == 0 && !p.watched {
+			p.cancel()
+		}
+		p.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// Cancel stops the background polling started by Done or Wait.
+// The task keeps running on the API side; Poll, Wait and Done can still be used afterwards.
+func (p *Pending[S]) Cancel() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.watched = false
+	p.cancel()
+}
+
+// Poll checks the task once. It reports false if the solution is not ready yet
+// or the task has failed, in which case the error is returned.
+func (p *Pending[S]) Poll(ctx context.Context) (*Solved[S], bool, error) {
+	select {
+	case <-p.done:
+		return p.sol, p.sol != nil, p.err
+	default:
+	}
+	res, err := p.s.GetTaskResult(ctx, p.id)
+	if err == nil || errors.As(err, new(Error)) {
+		p.mu.Lock()
+		p.polls++
+		p.mu.Unlock()
+	}
+	if err != nil {
+		// API errors such as CaptchaUnsolvable are final for the task.
+		if errors.As(err, new(Error)) {
+			p.fail(err)
+		}
+		return nil, false, err
+	}
+	if !res.ready() {
+		return nil, false, nil
+	}
+	p.finish(res)
+	return p.sol, p.sol != nil, p.err
+}
+
+// MarshalJSON encodes the task ID, type and creation time, plus the solution if it is already known.
+func (p *Pending[S]) MarshalJSON() ([]byte, error) {
+	state := pendingState{TaskID: p.id, Type: p.typ, CreatedAt: p.created}
+	select {
+	case <-p.done:
+		if p.sol != nil {
+			state.Solution = p.sol.Raw
+		}
+	default:
+	}
+	return json.Marshal(state)
+}
+
+// start begins polling the task in the background unless it is already polled or done,
+// and returns the polling in progress, or nil if the task is done.
+// It must be called with p.mu held.
+func (p *Pending[S]) start() *pendingRun {
+	if p.run != nil {
+		return p.run
+	}
+	select {
+	case <-p.done:
+		return nil
+	default:
+	}
+	ctx, stop := context.WithCancel(context.Background())
+	run := &pendingRun{stop: stop, timeout: make(chan struct{})}
+	p.run = run
+	go func() {
+		delay := p.s.initialDelay - time.Since(p.created)
+		res, polls, err := p.s.wait(ctx, p.id, p.typ, max(delay, 0), p.s.maxPolls)
+		stopped := err != nil && ctx.Err() != nil
+		stop()
+		p.mu.Lock()
+		p.polls += polls
+		if p.run == run {
+			p.run = nil
+		}
+		// A poll timeout leaves the task resumable: the waiters get the error
+		// and Done keeps polling with a new budget.
+		timedOut := !stopped && errors.Is(err, ErrPollTimeout)
+		if timedOut {
+			run.err = err
+			close(run.timeout)
+			if p.watched {
+				p.start()
+			}
+		}
+		p.mu.Unlock()
+		switch {
+		case stopped, timedOut:
+		case err != nil:
+			p.fail(err)
+		default:
+			p.finish(res)
+		}
+	}()
+	return run
+}
+
+// cancel stops the background polling. It must be called with p.mu held.
+func (p *Pending[S]) cancel() {
+	if p.run != nil {
+		p.run.stop()
+		p.run = nil
+	}
+}
+
+// finish completes the task with the given result, once.
+func (p *Pending[S]) finish(res *Result) {
+	p.once.Do(func() {
+		p.mu.Lock()
+		polls := p.polls
+		p.mu.Unlock()
+		sol := &Solved[S]{
+			TaskID:    p.id,
+			Type:      p.typ,
+			CreatedAt: p.created,
+			ReadyAt:   time.Now(),
+			PollCount: polls,
+			Raw:       res.Solution,
+		}
+		if err := res.Unmarshal(&sol.Solution); err != nil {
+			p.err = err
+		} else {
+			p.sol = sol
+		}
+		close(p.done)
+	})
+}
+
+// fail completes the task with an error, once.
+func (p *Pending[S]) fail(err error) {
+	p.once.Do(func() {
+		p.err = err
+		close(p.done)
+	})
+}
@@ -0,0 +1,266 @@
+package capsolver_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nukilabs/capsolver"
+	"github.com/nukilabs/capsolver/capsolvertest"
+)
+
+var turnstile = capsolver.AntiTurnstileTask{WebsiteURL: "https://example.com", WebsiteKey: "key"}
+
+func TestPendingPollCount(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}, Polls: 2})
+	ctx := context.Background()
+
+	p, err := capsolver.Submit[capsolver.AntiTurnstileSolution](ctx, srv.Session(), turnstile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 2; i++ {
+		if _, ready, err := p.Poll(ctx); ready || err != nil {
+			t.Fatalf("poll %d: ready = %v, err = %v", i, ready, err)
+		}
+	}
+	sol, ready, err := p.Poll(ctx)
+	if !ready || err != nil {
+		t.Fatalf("poll 3: ready = %v, err = %v", ready, err)
+	}
+	if sol.Solution.Token != "token" || sol.PollCount != 3 {
+		t.Errorf("solution = %+v, want token after 3 polls", sol)
+	}
+}
+
+func TestPendingWaitCancelStopsPolling(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}, Polls: 1000})
+	s := srv.Session(capsolver.WithPollInterval(5 * time.Millisecond))
+
+	p, err := capsolver.Submit[capsolver.AntiTurnstileSolution](context.Background(), s, turnstile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if _, err := p.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+	assertPollingStopped(t, srv)
+
+	// Waiting again resumes polling.
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}})
+	sol, err := p.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sol.Solution.Token != "token" {
+		t.Errorf("token = %q, want %q", sol.Solution.Token, "token")
+	}
+	// The polls of the cancelled Wait are counted too.
+	if n := countRequests(srv, "/getTaskResult"); n < 2 || sol.PollCount < n || sol.PollCount > n+1 {
+		t.Errorf("PollCount = %d, want the %d polls sent", sol.PollCount, n)
+	}
+}
+
+func TestPendingCancel(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}, Polls: 1000})
+	s := srv.Session(capsolver.WithPollInterval(5 * time.Millisecond))
+
+	p, err := capsolver.Submit[capsolver.AntiTurnstileSolution](context.Background(), s, turnstile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := p.Done()
+	time.Sleep(20 * time.Millisecond)
+	p.Cancel()
+	assertPollingStopped(t, srv)
+	select {
+	case <-done:
+		t.Fatal("Done closed by Cancel")
+	default:
+	}
+}
+
+// assertPollingStopped fails if getTaskResult requests keep arriving.
+func assertPollingStopped(t *testing.T, srv *capsolvertest.Server) {
+	t.Helper()
+	time.Sleep(20 * time.Millisecond)
+	before := countRequests(srv, "/getTaskResult")
+	time.Sleep(50 * time.Millisecond)
+	if after := countRequests(srv, "/getTaskResult"); after != before {
+		t.Errorf("getTaskResult requests went from %d to %d after cancel", before, after)
+	}
+}
+
+func TestPendingPollTimeoutResumes(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}, Polls: 3})
+	ctx := context.Background()
+
+	p, err := capsolver.Submit[capsolver.AntiTurnstileSolution](ctx, srv.Session(capsolver.WithMaxPolls(2)), turnstile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var timeout *capsolver.PollTimeoutError
+	if _, err := p.Wait(ctx); !errors.As(err, &timeout) || timeout.Polls != 2 {
+		t.Fatalf("err = %v, want poll timeout after 2 polls", err)
+	}
+	if sol, ready, err := p.Poll(ctx); sol != nil || ready || err != nil {
+		t.Fatalf("Poll() = %v, %v, %v after timeout, want not ready", sol, ready, err)
+	}
+	sol, err := p.Wait(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sol.Solution.Token != "token" || sol.PollCount != 4 {
+		t.Errorf("solution = %+v, want token after 4 polls", sol)
+	}
+}
+
+func TestPendingPollFailed(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{PollError: capsolver.CaptchaUnsolvable})
+	ctx := context.Background()
+
+	p, err := capsolver.Submit[capsolver.AntiTurnstileSolution](ctx, srv.Session(), turnstile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 2; i++ {
+		if sol, ready, err := p.Poll(ctx); sol != nil || ready || !errors.Is(err, capsolver.CaptchaUnsolvable) {
+			t.Fatalf("poll %d: Poll() = %v, %v, %v, want %s", i, sol, ready, err, capsolver.CaptchaUnsolvable)
+		}
+	}
+	if n := countRequests(srv, "/getTaskResult"); n != 1 {
+		t.Errorf("getTaskResult requests = %d, want 1", n)
+	}
+}
+
+func TestPendingResume(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}, Polls: 1})
+	ctx := context.Background()
+
+	p, err := capsolver.Submit[capsolver.AntiTurnstileSolution](ctx, srv.Session(), turnstile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Resume in a fresh session, as another process would.
+	resumed, err := capsolver.Resume[capsolver.AntiTurnstileSolution](srv.Session(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.TaskID() != p.TaskID() {
+		t.Errorf("TaskID() = %q, want %q", resumed.TaskID(), p.TaskID())
+	}
+	sol, err := resumed.Wait(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sol.Solution.Token != "token" || sol.TaskID != p.TaskID() || sol.Type != turnstile.TaskType() || sol.PollCount != 2 {
+		t.Errorf("solution = %+v, want token of %s after 2 polls", sol, p.TaskID())
+	}
+	if n := countRequests(srv, "/createTask"); n != 1 {
+		t.Errorf("createTask requests = %d, want 1", n)
+	}
+}
+
+func TestPendingResumeSolved(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(image.TaskType(), capsolvertest.Behavior{Solution: capsolver.ImageToTextSolution{Text: "abc"}, Sync: true})
+	ctx := context.Background()
+
+	p, err := capsolver.Submit[capsolver.ImageToTextSolution](ctx, srv.Session(), image)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resumed, err := capsolver.Resume[capsolver.ImageToTextSolution](srv.Session(), data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sol, ready, err := resumed.Poll(ctx)
+	if !ready || err != nil || sol.Solution.Text != "abc" {
+		t.Fatalf("Poll() = %+v, %v, %v, want solution", sol, ready, err)
+	}
+	if sol, err = resumed.Wait(ctx); err != nil || sol.Solution.Text != "abc" {
+		t.Fatalf("Wait() = %+v, %v, want solution", sol, err)
+	}
+	if n := countRequests(srv, "/getTaskResult"); n != 0 {
+		t.Errorf("getTaskResult requests = %d, want 0", n)
+	}
+}
+
+func TestSubmitProxyBanned(t *testing.T) {
+	banned := capsolver.MustParseProxy("http://127.0.0.1:8001")
+	good := capsolver.MustParseProxy("http://127.0.0.1:8002")
+	task := capsolver.ReCaptchaV2Task{WebsiteURL: "https://example.com", WebsiteKey: "key"}
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(task.WithProxy(good).TaskType(), capsolvertest.Behavior{Solution: capsolver.ReCaptchaV2Solution{GRecaptchaResponse: "token"}, Polls: 1})
+	ban := func(next capsolver.Handler) capsolver.Handler {
+		return func(ctx context.Context, endpoint string, payload capsolver.Payload) (*capsolver.Result, error) {
+			if raw, ok := payload.Task.(json.RawMessage); ok && strings.Contains(string(raw), banned.String()) {
+				return &capsolver.Result{Error: capsolver.Error{ID: 1, Code: capsolver.ProxyBanned}}, nil
+			}
+			return next(ctx, endpoint, payload)
+		}
+	}
+	var events []capsolver.SolveEvent
+	hook := func(ctx context.Context, ev capsolver.SolveEvent) (context.Context, func(capsolver.SolveEvent)) {
+		return ctx, func(ev capsolver.SolveEvent) { events = append(events, ev) }
+	}
+	pool := capsolver.NewProxyPool([]capsolver.Proxy{banned, good})
+	s := srv.Session(capsolver.WithProxyPool(pool), capsolver.WithMiddleware(ban), capsolver.WithSolveHook(hook))
+
+	p, err := capsolver.Submit[capsolver.ReCaptchaV2Solution](context.Background(), s, task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sol, err := p.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sol.Solution.GRecaptchaResponse != "token" {
+		t.Errorf("token = %q, want %q", sol.Solution.GRecaptchaResponse, "token")
+	}
+	if pool.Available() != 1 {
+		t.Errorf("Available() = %d, want 1 after the ban", pool.Available())
+	}
+	if len(events) != 2 || !errors.Is(events[0].Err, capsolver.ProxyBanned) || events[1].Err != nil || events[1].TaskID != p.TaskID() {
+		t.Errorf("solve events = %+v, want a banned attempt and the submitted task", events)
+	}
+}
+
+func TestPendingDoneAfterPollTimeout(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}, Polls: 3})
+
+	p, err := capsolver.Submit[capsolver.AntiTurnstileSolution](context.Background(), srv.Session(capsolver.WithMaxPolls(1)), turnstile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-p.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Done not closed after the poll budget was used up")
+	}
+	sol, ready, err := p.Poll(context.Background())
+	if !ready || err != nil || sol.PollCount != 4 {
+		t.Fatalf("Poll() = %+v, %v, %v, want solution after 4 polls", sol, ready, err)
+	}
+}
@@ -0,0 +1,171 @@
+package capsolver
+
+import (
+	"container/heap"
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// poller runs the getTaskResult requests of all in-flight tasks of a session
+// from a single goroutine and timer, instead of one sleep loop per task.
+// It is started on demand and exits once no task is pending.
+type poller struct {
+	s *Session
+	// interval is the minimum time between two requests, derived from the rate cap.
+	interval time.Duration
+
+	mu       sync.Mutex
+	queue    pollQueue
+	running  bool
+	nextSlot time.Time
+	wake     chan struct{}
+}
+
+// pollEntry is a task waiting for its solution.
+type pollEntry struct {
+	ctx       context.Context
+	id        string
+	typ       string
+	due       time.Time
+	start     time.Time
+	polls     atomic.Int64 // getTaskResult requests sent, read by wait on cancellation
+	maxPolls  int
+	status    Status
+	cancelled atomic.Bool
+	done      chan pollResult
+}
+
+type pollResult struct {
+	res   *Result
+	polls int
+	err   error
+}
+
+func newPoller(s *Session) *poller {
+	return &poller{s: s, wake: make(chan struct{}, 1)}
+}
+
+// wait registers the task and blocks until it is ready, failed, polled maxPolls
+// times or ctx is done. The first poll is sent after delay.
+func (p *poller) wait(ctx context.Context, id, typ string, delay time.Duration, maxPolls int) (*Result, int, error) {
+	now := time.Now()
+	e := &pollEntry{
+		ctx:      ctx,
+		id:       id,
+		typ:      typ,
+		due:      now.Add(delay),
+		start:    now,
+		maxPolls: maxPolls,
+		done:     make(chan pollResult, 1),
+	}
+	p.schedule(e)
+	select {
+	case r := <-e.done:
+		return r.res, r.polls, r.err
+	case <-ctx.Done():
+		e.cancelled.Store(true)
+		return nil, int(e.polls.Load()), ctx.Err()
+	}
+}
+
+// schedule adds the entry to the queue and makes sure the loop is running.
+func (p *poller) schedule(e *pollEntry) {
+	p.mu.Lock()
+	heap.Push(&p.queue, e)
+	if !p.running {
+		p.running = true
+		go p.loop()
+	}
+	p.mu.Unlock()
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (p *poller) loop() {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		p.mu.Lock()
+		for len(p.queue) > 0 && p.queue[0].cancelled.Load() {
+			heap.Pop(&p.queue)
+		}
+		if len(p.queue) == 0 {
+			p.running = false
+			p.mu.Unlock()
+			return
+		}
+		now := time.Now()
+		d := p.queue[0].due.Sub(now)
+		if d <= 0 {
+			d = p.nextSlot.Sub(now)
+		}
+		if d <= 0 {
+			e := heap.Pop(&p.queue).(*pollEntry)
+			p.nextSlot = now.Add(p.interval)
+			p.mu.Unlock()
+			go p.poll(e)
+			continue
+		}
+		p.mu.Unlock()
+
+		timer.Reset(d)
+		select {
+		case <-timer.C:
+		case <-p.wake:
+		}
+	}
+}
+
+// poll sends a single getTaskResult request and either completes the entry or reschedules it.
+func (p *poller) poll(e *pollEntry) {
+	polls := int(e.polls.Add(1))
+	res, err := p.s.getTaskResult(e.ctx, e.id)
+	if err == nil {
+		logger := p.s.logger
+		attrs := []slog.Attr{slog.String("taskType", e.typ), slog.String("taskId", e.id), slog.Int("poll", polls), slog.String("status", string(res.Status)), slog.Duration("elapsed", time.Since(e.start))}
+		logger.LogAttrs(e.ctx, slog.LevelDebug, "capsolver poll", attrs...)
+		if res.Status != e.status {
+			logger.LogAttrs(e.ctx, slog.LevelInfo, "capsolver task status changed", append(attrs, slog.String("previous", string(e.status)))...)
+			e.status = res.Status
+		}
+	}
+	switch {
+	case err != nil:
+		e.done <- pollResult{polls: polls, err: annotate(err, e.id, e.typ)}
+	case res.ready():
+		if res.TaskId == "" {
+			res.TaskId = e.id
+		}
+		e.done <- pollResult{res: res, polls: polls}
+	case polls >= e.maxPolls:
+		e.done <- pollResult{polls: polls, err: &PollTimeoutError{TaskID: e.id, Polls: polls, Elapsed: time.Since(e.start)}}
+	case !e.cancelled.Load():
+		e.due = time.Now().Add(p.s.pollInterval)
+		p.schedule(e)
+	}
+}
+
+// pollQueue is a min-heap of entries ordered by due time.
+type pollQueue []*pollEntry
+
+func (q pollQueue) Len() int           { return len(q) }
+func (q pollQueue) Less(i, j int) bool { return q[i].due.Before(q[j].due) }
+func (q pollQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *pollQueue) Push(x any) {
+	*q = append(*q, x.(*pollEntry))
+}
+
+func (q *pollQueue) Pop() any {
+	old := *q
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return e
+}
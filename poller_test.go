@@ -0,0 +1,164 @@
+package capsolver_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nukilabs/capsolver"
+	"github.com/nukilabs/capsolver/capsolvertest"
+)
+
+// pollTimes is a middleware that records the time of every getTaskResult request.
+type pollTimes struct {
+	mu    sync.Mutex
+	times []time.Time
+}
+
+func (p *pollTimes) middleware(next capsolver.Handler) capsolver.Handler {
+	return func(ctx context.Context, endpoint string, payload capsolver.Payload) (*capsolver.Result, error) {
+		if endpoint == "/getTaskResult" {
+			p.mu.Lock()
+			p.times = append(p.times, time.Now())
+			p.mu.Unlock()
+		}
+		return next(ctx, endpoint, payload)
+	}
+}
+
+func TestPollerConcurrentTasks(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}, Polls: 3})
+	s := srv.Session()
+
+	const tasks = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, tasks)
+	for range tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sol, err := s.SolveAntiTurnstile(turnstile)
+			switch {
+			case err != nil:
+				errs <- err
+			case sol.PollCount != 4:
+				errs <- errors.New("unexpected poll count")
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if n := countRequests(srv, "/getTaskResult"); n != tasks*4 {
+		t.Errorf("getTaskResult requests = %d, want %d", n, tasks*4)
+	}
+}
+
+func TestPollerCancel(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}, Polls: 1000})
+	s := srv.Session(capsolver.WithPollInterval(5 * time.Millisecond))
+
+	// The first task keeps polling while the second is cancelled.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kept := make(chan error, 1)
+	go func() {
+		_, err := s.SolveAntiTurnstileContext(ctx, turnstile)
+		kept <- err
+	}()
+	eventually(t, func() bool { return countRequests(srv, "/createTask") == 1 })
+
+	short, cancelShort := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancelShort()
+	if _, err := s.SolveAntiTurnstileContext(short, turnstile); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	polls := func() map[string]int {
+		n := make(map[string]int)
+		for _, r := range srv.Requests() {
+			if r.Endpoint == "/getTaskResult" {
+				n[r.Payload.TaskID]++
+			}
+		}
+		return n
+	}
+	time.Sleep(20 * time.Millisecond)
+	before := polls()
+	time.Sleep(50 * time.Millisecond)
+	after := polls()
+	if len(after) != 2 {
+		t.Fatalf("polled %d tasks, want 2", len(after))
+	}
+	for id, n := range after {
+		switch moved := n != before[id]; {
+		case id == "capsolvertest-1" && !moved:
+			t.Errorf("%s stopped polling", id)
+		case id == "capsolvertest-2" && moved:
+			t.Errorf("%s kept polling after cancel: %d to %d requests", id, before[id], n)
+		}
+	}
+
+	cancel()
+	if err := <-kept; !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want %v", err, context.Canceled)
+	}
+	assertPollingStopped(t, srv)
+}
+
+func TestPollRate(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}, Polls: 2})
+	var pt pollTimes
+	s := srv.Session(capsolver.WithPollRate(250), capsolver.WithMiddleware(pt.middleware))
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.SolveAntiTurnstile(turnstile); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 30 polls at 4ms apart take at least 116ms; allow for scheduling of the first one.
+	if len(pt.times) != 30 {
+		t.Fatalf("getTaskResult requests = %d, want 30", len(pt.times))
+	}
+	slices.SortFunc(pt.times, time.Time.Compare)
+	if span := pt.times[29].Sub(pt.times[0]); span < 100*time.Millisecond {
+		t.Errorf("30 polls took %v, want at least 100ms at 250 rps", span)
+	}
+}
+
+func TestPollerCancelReportsPolls(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}, Polls: 1000})
+	events := make(chan capsolver.SolveEvent, 1)
+	hook := func(ctx context.Context, ev capsolver.SolveEvent) (context.Context, func(capsolver.SolveEvent)) {
+		return ctx, func(ev capsolver.SolveEvent) { events <- ev }
+	}
+	s := srv.Session(capsolver.WithPollInterval(5*time.Millisecond), capsolver.WithSolveHook(hook))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if _, err := s.SolveAntiTurnstileContext(ctx, turnstile); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+	ev := <-events
+	assertPollingStopped(t, srv)
+	// A poll cut short by the cancellation may not have reached the server.
+	if n := countRequests(srv, "/getTaskResult"); n == 0 || ev.Polls < n || ev.Polls > n+1 {
+		t.Errorf("SolveEvent.Polls = %d, want the %d polls sent", ev.Polls, n)
+	}
+}
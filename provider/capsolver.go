@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/nukilabs/capsolver"
+)
+
+// CapSolver is the Provider for CapSolver, backed by a capsolver.Solver such as *capsolver.Session.
+type CapSolver struct {
+	solver capsolver.Solver
+}
+
+// NewCapSolver creates a CapSolver provider that solves tasks with s.
+func NewCapSolver(s capsolver.Solver) *CapSolver {
+	return &CapSolver{solver: s}
+}
+
+// Name implements Provider.
+func (c *CapSolver) Name() string {
+	return "capsolver"
+}
+
+// Supports implements Provider. CapSolver supports every task.
+func (c *CapSolver) Supports(task capsolver.Task) bool {
+	return true
+}
+
+// Solve implements Provider.
+func (c *CapSolver) Solve(ctx context.Context, task capsolver.Task) (*Result, error) {
+	res, err := c.solver.SolveContext(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Provider: c.Name(), TaskID: res.TaskId, Raw: res.Solution}, nil
+}
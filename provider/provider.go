@@ -0,0 +1,61 @@
+// Package provider solves captcha tasks across several solving services.
+//
+// Tasks are the task structs of the capsolver package. Each Provider adapts
+// them to one service and returns solutions in CapSolver's solution schema,
+// so they decode into the capsolver solution types regardless of the service.
+// A Router fails over between providers by priority, health and cost.
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/nukilabs/capsolver"
+)
+
+// ErrUnsupported is returned when no provider supports a task.
+var ErrUnsupported = errors.New("[capsolver] task not supported by any provider")
+
+// Provider solves tasks with one solving service.
+type Provider interface {
+	// Name returns the name of the service, e.g. "capsolver".
+	Name() string
+	// Supports reports whether the provider can solve the task.
+	Supports(task capsolver.Task) bool
+	// Solve solves the task and waits for the solution.
+	Solve(ctx context.Context, task capsolver.Task) (*Result, error)
+}
+
+// Result is a solution returned by a provider.
+type Result struct {
+	// Provider is the name of the provider that solved the task.
+	Provider string
+	// TaskID is the ID the provider assigned to the task.
+	TaskID string
+	// Raw is the solution in CapSolver's solution schema.
+	Raw json.RawMessage
+}
+
+// Solved is a decoded solution returned by Solve.
+type Solved[S any] struct {
+	// Solution is the decoded solution.
+	Solution S
+	// Provider is the name of the provider that solved the task.
+	Provider string
+	// TaskID is the ID the provider assigned to the task.
+	TaskID string
+}
+
+// Solve solves the task with p and decodes the solution into S.
+func Solve[S any](ctx context.Context, p Provider, task capsolver.Task) (*Solved[S], error) {
+	res, err := p.Solve(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+	sol := &Solved[S]{Provider: res.Provider, TaskID: res.TaskID}
+	if err := json.Unmarshal(res.Raw, &sol.Solution); err != nil {
+		return nil, err
+	}
+	return sol, nil
+}
@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nukilabs/capsolver"
+)
+
+// DefaultCooldown is how long a provider is considered unhealthy after a failover.
+const DefaultCooldown = 5 * time.Minute
+
+// Router is a Provider that solves tasks with the best available provider.
+// Providers are tried by priority (highest first), then cost (lowest first),
+// skipping unhealthy ones until no healthy provider is left. A provider that
+// fails with a failover error, by default ServiceUnavailable or ZeroBalance,
+// is marked unhealthy for the cooldown and the next provider is tried.
+type Router struct {
+	cooldown time.Duration
+	failover func(error) bool
+
+	mu     sync.Mutex
+	routes []*route
+}
+
+type route struct {
+	provider Provider
+	priority int
+	cost     float64
+	until    time.Time
+	failures int
+}
+
+// RouterOption configures a Router.
+type RouterOption func(*Router)
+
+// WithCooldown sets how long a provider is considered unhealthy after a failover.
+func WithCooldown(d time.Duration) RouterOption {
+	return func(r *Router) {
+		r.cooldown = d
+	}
+}
+
+// WithFailover sets the function that decides whether an error moves on to the next provider.
+func WithFailover(fn func(error) bool) RouterOption {
+	return func(r *Router) {
+		r.failover = fn
+	}
+}
+
+// RouteOption configures a provider added to a Router.
+type RouteOption func(*route)
+
+// WithPriority sets the priority of the provider; higher priorities are tried first.
+func WithPriority(n int) RouteOption {
+	return func(r *route) {
+		r.priority = n
+	}
+}
+
+// WithCost sets the cost per solve of the provider, used to order providers of equal priority.
+func WithCost(cost float64) RouteOption {
+	return func(r *route) {
+		r.cost = cost
+	}
+}
+
+// NewRouter creates a Router without providers.
+func NewRouter(opts ...RouterOption) *Router {
+	r := &Router{
+		cooldown: DefaultCooldown,
+		failover: Failover,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Failover reports whether err is ServiceUnavailable or ZeroBalance.
+// It is the default failover function of a Router.
+func Failover(err error) bool {
+	return errors.Is(err, capsolver.ServiceUnavailable) || errors.Is(err, capsolver.ZeroBalance)
+}
+
+// Add adds a provider to the router.
+func (r *Router) Add(p Provider, opts ...RouteOption) {
+	rt := &route{provider: p}
+	for _, opt := range opts {
+		opt(rt)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = append(r.routes, rt)
+}
+
+// Name implements Provider.
+func (r *Router) Name() string {
+	return "router"
+}
+
+// Supports implements Provider.
+func (r *Router) Supports(task capsolver.Task) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, rt := range r.routes {
+		if rt.provider.Supports(task) {
+			return true
+		}
+	}
+	return false
+}
+
+// Solve implements Provider. If every provider fails over, the joined errors are returned.
+func (r *Router) Solve(ctx context.Context, task capsolver.Task) (*Result, error) {
+	routes := r.candidates(task)
+	if len(routes) == 0 {
+		return nil, ErrUnsupported
+	}
+	var errs []error
+	for _, rt := range routes {
+		res, err := rt.provider.Solve(ctx, task)
+		if err == nil {
+			r.markHealthy(rt)
+			return res, nil
+		}
+		if ctx.Err() != nil || !r.failover(err) {
+			return nil, err
+		}
+		r.markUnhealthy(rt)
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(errs...)
+}
+
+// candidates returns the providers supporting the task in the order they are tried.
+func (r *Router) candidates(task capsolver.Task) []*route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	var routes []*route
+	for _, rt := range r.routes {
+		if rt.provider.Supports(task) {
+			routes = append(routes, rt)
+		}
+	}
+	slices.SortStableFunc(routes, func(a, b *route) int {
+		if ha, hb := !now.Before(a.until), !now.Before(b.until); ha != hb {
+			if ha {
+				return -1
+			}
+			return 1
+		}
+		if c := cmp.Compare(b.priority, a.priority); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.cost, b.cost)
+	})
+	return routes
+}
+
+func (r *Router) markHealthy(rt *route) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rt.until = time.Time{}
+	rt.failures = 0
+}
+
+func (r *Router) markUnhealthy(rt *route) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rt.until = time.Now().Add(r.cooldown)
+	rt.failures++
+}
+
+// Health describes the state of a provider in a Router.
+type Health struct {
+	// Provider is the name of the provider.
+	Provider string
+	// Healthy reports whether the provider is outside its cooldown.
+	Healthy bool
+	// Until is the end of the cooldown of an unhealthy provider.
+	Until time.Time
+	// Failures is the number of failovers since the last successful solve.
+	Failures int
+}
+
+// Health returns the state of every provider, in the order they were added.
+func (r *Router) Health() []Health {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	health := make([]Health, len(r.routes))
+	for i, rt := range r.routes {
+		health[i] = Health{
+			Provider: rt.provider.Name(),
+			Healthy:  true,
+			Failures: rt.failures,
+		}
+		if now.Before(rt.until) {
+			health[i].Healthy = false
+			health[i].Until = rt.until
+		}
+	}
+	return health
+}
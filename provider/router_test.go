@@ -0,0 +1,221 @@
+package provider_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nukilabs/capsolver"
+	"github.com/nukilabs/capsolver/capsolvertest"
+	"github.com/nukilabs/capsolver/provider"
+)
+
+// fakeProvider fails with err, or solves every task it supports.
+type fakeProvider struct {
+	name     string
+	err      error
+	supports bool
+	calls    *[]string
+}
+
+func (f *fakeProvider) Name() string                      { return f.name }
+func (f *fakeProvider) Supports(task capsolver.Task) bool { return f.supports }
+
+func (f *fakeProvider) Solve(ctx context.Context, task capsolver.Task) (*provider.Result, error) {
+	*f.calls = append(*f.calls, f.name)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &provider.Result{Provider: f.name, TaskID: f.name + "-1", Raw: []byte(`{"token":"` + f.name + `"}`)}, nil
+}
+
+var task = capsolver.AntiTurnstileTask{WebsiteURL: "https://example.com", WebsiteKey: "site"}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRouterOrder(t *testing.T) {
+	var calls []string
+	unavailable := capsolver.Error{Code: capsolver.ServiceUnavailable}
+	r := provider.NewRouter()
+	r.Add(&fakeProvider{name: "cheap", err: unavailable, supports: true, calls: &calls}, provider.WithCost(1))
+	r.Add(&fakeProvider{name: "unsupported", supports: false, calls: &calls}, provider.WithPriority(100))
+	r.Add(&fakeProvider{name: "expensive", err: unavailable, supports: true, calls: &calls}, provider.WithCost(5))
+	r.Add(&fakeProvider{name: "preferred", err: unavailable, supports: true, calls: &calls}, provider.WithPriority(10), provider.WithCost(9))
+	r.Add(&fakeProvider{name: "free", err: unavailable, supports: true, calls: &calls})
+
+	_, err := r.Solve(context.Background(), task)
+	if !errors.Is(err, capsolver.ServiceUnavailable) {
+		t.Fatalf("err = %v, want %s", err, capsolver.ServiceUnavailable)
+	}
+	if want := []string{"preferred", "free", "cheap", "expensive"}; !equal(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestRouterFailover(t *testing.T) {
+	for _, code := range []capsolver.ErrorCode{capsolver.ServiceUnavailable, capsolver.ZeroBalance} {
+		t.Run(string(code), func(t *testing.T) {
+			var calls []string
+			r := provider.NewRouter()
+			r.Add(&fakeProvider{name: "primary", err: capsolver.Error{Code: code}, supports: true, calls: &calls}, provider.WithPriority(1))
+			r.Add(&fakeProvider{name: "backup", supports: true, calls: &calls})
+
+			sol, err := provider.Solve[capsolver.AntiTurnstileSolution](context.Background(), r, task)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sol.Solution.Token != "backup" || sol.Provider != "backup" || sol.TaskID != "backup-1" {
+				t.Errorf("solved = %+v, want backup", sol)
+			}
+			if want := []string{"primary", "backup"}; !equal(calls, want) {
+				t.Errorf("calls = %v, want %v", calls, want)
+			}
+			health := r.Health()
+			if health[0].Healthy || health[0].Failures != 1 || health[0].Until.IsZero() {
+				t.Errorf("primary health = %+v, want unhealthy after 1 failure", health[0])
+			}
+			if !health[1].Healthy || health[1].Failures != 0 {
+				t.Errorf("backup health = %+v, want healthy", health[1])
+			}
+		})
+	}
+}
+
+func TestRouterNoFailover(t *testing.T) {
+	var calls []string
+	r := provider.NewRouter()
+	r.Add(&fakeProvider{name: "primary", err: capsolver.Error{Code: capsolver.InvalidTaskData}, supports: true, calls: &calls}, provider.WithPriority(1))
+	r.Add(&fakeProvider{name: "backup", supports: true, calls: &calls})
+
+	_, err := r.Solve(context.Background(), task)
+	if !errors.Is(err, capsolver.InvalidTaskData) {
+		t.Fatalf("err = %v, want %s", err, capsolver.InvalidTaskData)
+	}
+	if want := []string{"primary"}; !equal(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+	if !r.Health()[0].Healthy {
+		t.Error("primary marked unhealthy by a non-failover error")
+	}
+}
+
+func TestRouterCustomFailover(t *testing.T) {
+	var calls []string
+	r := provider.NewRouter(provider.WithFailover(func(err error) bool {
+		return errors.Is(err, capsolver.CaptchaUnsolvable)
+	}))
+	r.Add(&fakeProvider{name: "primary", err: capsolver.Error{Code: capsolver.CaptchaUnsolvable}, supports: true, calls: &calls}, provider.WithPriority(1))
+	r.Add(&fakeProvider{name: "backup", supports: true, calls: &calls})
+
+	if _, err := r.Solve(context.Background(), task); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"primary", "backup"}; !equal(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestRouterCooldown(t *testing.T) {
+	var calls []string
+	primary := &fakeProvider{name: "primary", err: capsolver.Error{Code: capsolver.ZeroBalance}, supports: true, calls: &calls}
+	r := provider.NewRouter(provider.WithCooldown(50 * time.Millisecond))
+	r.Add(primary, provider.WithPriority(1))
+	r.Add(&fakeProvider{name: "backup", supports: true, calls: &calls})
+	ctx := context.Background()
+
+	if _, err := r.Solve(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+	// The unhealthy primary is skipped while the backup works.
+	primary.err = nil
+	calls = nil
+	if _, err := r.Solve(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"backup"}; !equal(calls, want) {
+		t.Errorf("calls during cooldown = %v, want %v", calls, want)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	calls = nil
+	res, err := r.Solve(ctx, task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Provider != "primary" || !equal(calls, []string{"primary"}) {
+		t.Errorf("calls after cooldown = %v, want [primary]", calls)
+	}
+	if h := r.Health()[0]; !h.Healthy || h.Failures != 0 || !h.Until.IsZero() {
+		t.Errorf("primary health = %+v, want healthy", h)
+	}
+}
+
+func TestRouterUnhealthyLastResort(t *testing.T) {
+	var calls []string
+	only := &fakeProvider{name: "only", err: capsolver.Error{Code: capsolver.ServiceUnavailable}, supports: true, calls: &calls}
+	r := provider.NewRouter()
+	r.Add(only)
+	ctx := context.Background()
+
+	if _, err := r.Solve(ctx, task); !errors.Is(err, capsolver.ServiceUnavailable) {
+		t.Fatalf("err = %v, want %s", err, capsolver.ServiceUnavailable)
+	}
+	only.err = nil
+	if _, err := r.Solve(ctx, task); err != nil {
+		t.Fatalf("unhealthy provider not tried as last resort: %v", err)
+	}
+}
+
+func TestRouterUnsupported(t *testing.T) {
+	var calls []string
+	r := provider.NewRouter()
+	r.Add(&fakeProvider{name: "none", supports: false, calls: &calls})
+
+	if r.Supports(task) {
+		t.Error("Supports = true, want false")
+	}
+	if _, err := r.Solve(context.Background(), task); !errors.Is(err, provider.ErrUnsupported) {
+		t.Errorf("err = %v, want %v", err, provider.ErrUnsupported)
+	}
+	if len(calls) != 0 {
+		t.Errorf("calls = %v, want none", calls)
+	}
+}
+
+func TestRouterCapSolverToTwoCaptcha(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(task.TaskType(), capsolvertest.Behavior{Error: capsolver.ZeroBalance})
+	f := newTwoCaptcha(t)
+
+	r := provider.NewRouter()
+	r.Add(provider.NewCapSolver(srv.Session()), provider.WithPriority(1))
+	r.Add(f.provider())
+
+	sol, err := provider.Solve[capsolver.AntiTurnstileSolution](context.Background(), r, task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sol.Solution.Token != "answer" || sol.Provider != "2captcha" {
+		t.Errorf("solved = %+v, want 2captcha answer", sol)
+	}
+
+	srv.Handle(task.TaskType(), capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "capsolver"}, Sync: true})
+	sol, err = provider.Solve[capsolver.AntiTurnstileSolution](context.Background(), provider.NewCapSolver(srv.Session()), task)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sol.Solution.Token != "capsolver" || sol.Provider != "capsolver" || sol.TaskID == "" {
+		t.Errorf("solved = %+v, want capsolver token", sol)
+	}
+}
@@ -0,0 +1,310 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nukilabs/capsolver"
+)
+
+const (
+	// TwoCaptchaURL is the base URL of the 2captcha API.
+	TwoCaptchaURL = "https://2captcha.com"
+	// DefaultTwoCaptchaPollInterval is the delay between res.php requests.
+	DefaultTwoCaptchaPollInterval = 5 * time.Second
+	// DefaultTwoCaptchaMaxPolls is the number of res.php requests before giving up.
+	DefaultTwoCaptchaMaxPolls = 60
+)
+
+// TwoCaptcha is the Provider for services with the 2captcha in.php/res.php API.
+// It supports ReCaptchaV2Task, ReCaptchaV3Task, AntiTurnstileTask and ImageToTextTask.
+type TwoCaptcha struct {
+	name         string
+	key          string
+	baseURL      string
+	client       *http.Client
+	pollInterval time.Duration
+	maxPolls     int
+}
+
+// TwoCaptchaOption configures a TwoCaptcha provider.
+type TwoCaptchaOption func(*TwoCaptcha)
+
+// WithTwoCaptchaName sets the provider name ("2captcha" by default),
+// for services that implement the same API.
+func WithTwoCaptchaName(name string) TwoCaptchaOption {
+	return func(t *TwoCaptcha) {
+		t.name = name
+	}
+}
+
+// WithTwoCaptchaBaseURL sets the base URL the in.php and res.php paths are appended to.
+func WithTwoCaptchaBaseURL(baseURL string) TwoCaptchaOption {
+	return func(t *TwoCaptcha) {
+		t.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithTwoCaptchaHTTPClient sets the HTTP client used for requests.
+func WithTwoCaptchaHTTPClient(client *http.Client) TwoCaptchaOption {
+	return func(t *TwoCaptcha) {
+		t.client = client
+	}
+}
+
+// WithTwoCaptchaPollInterval sets the delay between res.php requests.
+func WithTwoCaptchaPollInterval(d time.Duration) TwoCaptchaOption {
+	return func(t *TwoCaptcha) {
+		t.pollInterval = d
+	}
+}
+
+// WithTwoCaptchaMaxPolls sets the number of res.php requests before giving up.
+func WithTwoCaptchaMaxPolls(n int) TwoCaptchaOption {
+	return func(t *TwoCaptcha) {
+		t.maxPolls = n
+	}
+}
+
+// NewTwoCaptcha creates a TwoCaptcha provider with the given API key.
+func NewTwoCaptcha(key string, opts ...TwoCaptchaOption) *TwoCaptcha {
+	t := &TwoCaptcha{
+		name:         "2captcha",
+		key:          key,
+		baseURL:      TwoCaptchaURL,
+		client:       http.DefaultClient,
+		pollInterval: DefaultTwoCaptchaPollInterval,
+		maxPolls:     DefaultTwoCaptchaMaxPolls,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Name implements Provider.
+func (t *TwoCaptcha) Name() string {
+	return t.name
+}
+
+// Supports implements Provider.
+func (t *TwoCaptcha) Supports(task capsolver.Task) bool {
+	_, _, ok := twoCaptchaParams(task)
+	return ok
+}
+
+// Solve implements Provider.
+func (t *TwoCaptcha) Solve(ctx context.Context, task capsolver.Task) (*Result, error) {
+	params, solution, ok := twoCaptchaParams(task)
+	if !ok {
+		return nil, capsolver.Error{Code: capsolver.TaskNotSupported, Description: t.name, TaskType: task.TaskType()}
+	}
+	params.Set("key", t.key)
+	params.Set("json", "1")
+	typ := task.TaskType()
+	id, err := t.call(ctx, http.MethodPost, "/in.php", params, "", typ)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	query := url.Values{"key": {t.key}, "action": {"get"}, "id": {id}, "json": {"1"}}
+	for polls := 1; polls <= t.maxPolls; polls++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(t.pollInterval):
+		}
+		answer, err := t.call(ctx, http.MethodGet, "/res.php", query, id, typ)
+		if err == errNotReady {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		raw, err := json.Marshal(solution(answer))
+		if err != nil {
+			return nil, err
+		}
+		return &Result{Provider: t.name, TaskID: id, Raw: raw}, nil
+	}
+	return nil, &capsolver.PollTimeoutError{TaskID: id, Polls: t.maxPolls, Elapsed: time.Since(start)}
+}
+
+// errNotReady is returned by call while res.php reports the task as not ready.
+var errNotReady = errors.New("[capsolver] 2captcha task not ready")
+
+// twoCaptchaResponse is the JSON body of in.php and res.php responses.
+type twoCaptchaResponse struct {
+	Status    int    `json:"status"`
+	Request   string `json:"request"`
+	ErrorText string `json:"error_text"`
+}
+
+// call sends a request and returns the "request" field of a successful response.
+// The task ID and type are attached to API errors.
+func (t *TwoCaptcha) call(ctx context.Context, method, path string, params url.Values, taskID, taskType string) (string, error) {
+	var req *http.Request
+	var err error
+	if method == http.MethodPost {
+		req, err = http.NewRequestWithContext(ctx, method, t.baseURL+path, strings.NewReader(params.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	} else {
+		req, err = http.NewRequestWithContext(ctx, method, t.baseURL+path+"?"+params.Encode(), nil)
+	}
+	if err != nil {
+		return "", err
+	}
+	res, err := t.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("[capsolver] %s %s: %w", t.name, path, err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", fmt.Errorf("[capsolver] %s %s: %w", t.name, path, err)
+	}
+
+	var r twoCaptchaResponse
+	if err := json.Unmarshal(body, &r); err != nil {
+		if res.StatusCode < 200 || res.StatusCode > 299 {
+			return "", fmt.Errorf("[capsolver] %s %s: HTTP %d", t.name, path, res.StatusCode)
+		}
+		return "", fmt.Errorf("%w from %s %s", capsolver.ErrInvalidResponse, t.name, path)
+	}
+	switch {
+	case r.Status == 1:
+		return r.Request, nil
+	case r.Request == "CAPCHA_NOT_READY":
+		return "", errNotReady
+	}
+	description := t.name + " " + r.Request
+	if r.ErrorText != "" {
+		description += ": " + r.ErrorText
+	}
+	return "", capsolver.Error{ID: 1, Code: twoCaptchaCode(r.Request), Description: description, TaskID: taskID, TaskType: taskType}
+}
+
+// twoCaptchaCode maps 2captcha error codes onto CapSolver error codes,
+// so that errors.Is and the predicates of capsolver.Error work for both services.
+func twoCaptchaCode(code string) capsolver.ErrorCode {
+	switch code {
+	case "ERROR_ZERO_BALANCE":
+		return capsolver.ZeroBalance
+	case "ERROR_NO_SLOT_AVAILABLE":
+		return capsolver.ServiceUnavailable
+	case "ERROR_WRONG_USER_KEY", "ERROR_KEY_DOES_NOT_EXIST", "ERROR_IP_NOT_ALLOWED":
+		return capsolver.KeyDeniedAccess
+	case "IP_BANNED":
+		return capsolver.IPBanned
+	case "ERROR_CAPTCHA_UNSOLVABLE":
+		return capsolver.CaptchaUnsolvable
+	case "ERROR_WRONG_CAPTCHA_ID":
+		return capsolver.TaskIDInvalid
+	case "ERROR_BAD_PROXY", "ERROR_PROXY_CONNECTION_FAILED":
+		return capsolver.ProxyBanned
+	case "ERROR_ZERO_CAPTCHA_FILESIZE", "ERROR_TOO_BIG_CAPTCHA_FILESIZE", "ERROR_WRONG_FILE_EXTENSION", "ERROR_IMAGE_TYPE_NOT_SUPPORTED":
+		return capsolver.InvalidImage
+	case "ERROR_BAD_PARAMETERS", "ERROR_WRONG_GOOGLEKEY", "ERROR_GOOGLEKEY", "ERROR_PAGEURL", "ERROR_BAD_TOKEN_OR_PAGEURL":
+		return capsolver.InvalidTaskData
+	}
+	return capsolver.BadRequest
+}
+
+// twoCaptchaParams returns the in.php parameters of a task and a function
+// that converts the answer into a solution in CapSolver's schema.
+// Pointers to tasks are dereferenced.
+func twoCaptchaParams(task capsolver.Task) (url.Values, func(answer string) any, bool) {
+	if v := reflect.ValueOf(task); v.Kind() == reflect.Pointer && !v.IsNil() {
+		if t, ok := v.Elem().Interface().(capsolver.Task); ok {
+			task = t
+		}
+	}
+	params := url.Values{}
+	switch task := task.(type) {
+	case capsolver.ReCaptchaV2Task:
+		params.Set("method", "userrecaptcha")
+		params.Set("googlekey", task.WebsiteKey)
+		params.Set("pageurl", task.WebsiteURL)
+		if task.IsInvisible {
+			params.Set("invisible", "1")
+		}
+		if task.EnterprisePayload != nil {
+			params.Set("enterprise", "1")
+			if s, ok := task.EnterprisePayload["s"].(string); ok {
+				params.Set("data-s", s)
+			}
+		}
+		setProxy(params, task.Proxy)
+		return params, func(answer string) any {
+			return capsolver.ReCaptchaV2Solution{GRecaptchaResponse: answer}
+		}, true
+	case capsolver.ReCaptchaV3Task:
+		params.Set("method", "userrecaptcha")
+		params.Set("version", "v3")
+		params.Set("googlekey", task.WebsiteKey)
+		params.Set("pageurl", task.WebsiteURL)
+		if task.PageAction != "" {
+			params.Set("action", task.PageAction)
+		}
+		if task.EnterprisePayload != nil {
+			params.Set("enterprise", "1")
+		}
+		setProxy(params, task.Proxy)
+		return params, func(answer string) any {
+			return capsolver.ReCaptchaV3Solution{GRecaptchaResponse: answer}
+		}, true
+	case capsolver.AntiTurnstileTask:
+		params.Set("method", "turnstile")
+		params.Set("sitekey", task.WebsiteKey)
+		params.Set("pageurl", task.WebsiteURL)
+		if action := task.Metadata["action"]; action != "" {
+			params.Set("action", action)
+		}
+		if cdata := task.Metadata["cdata"]; cdata != "" {
+			params.Set("data", cdata)
+		}
+		return params, func(answer string) any {
+			return capsolver.AntiTurnstileSolution{Token: answer}
+		}, true
+	case capsolver.ImageToTextTask:
+		if len(task.Images) > 0 {
+			return nil, nil, false
+		}
+		params.Set("method", "base64")
+		params.Set("body", task.Body)
+		return params, func(answer string) any {
+			return capsolver.ImageToTextSolution{Text: answer}
+		}, true
+	}
+	return nil, nil, false
+}
+
+// setProxy sets the proxy parameters if the task has a proxy.
+func setProxy(params url.Values, p capsolver.Proxy) {
+	if p.IsZero() {
+		return
+	}
+	addr := net.JoinHostPort(p.Host, strconv.Itoa(p.Port))
+	if p.User != "" {
+		addr = p.User + ":" + p.Password + "@" + addr
+	}
+	scheme := p.Scheme
+	if scheme == "" {
+		scheme = capsolver.ProxyHTTP
+	}
+	params.Set("proxy", addr)
+	params.Set("proxytype", strings.ToUpper(string(scheme)))
+}
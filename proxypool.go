@@ -0,0 +1,202 @@
+package capsolver
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ErrNoProxy is returned when every proxy of a ProxyPool is quarantined.
+var ErrNoProxy = errors.New("[capsolver] no proxy available")
+
+// ProxyTask is implemented by tasks that can be solved through a proxy.
+// A Session with a ProxyPool fills in the proxy of such tasks when it is empty.
+type ProxyTask interface {
+	Task
+	// TaskProxy returns the proxy of the task.
+	TaskProxy() Proxy
+	// WithProxy returns a copy of the task that uses the given proxy.
+	WithProxy(p Proxy) Task
+	// SiteURL returns the URL of the target site, used for sticky proxy selection.
+	SiteURL() string
+}
+
+// ProxyStrategy selects the next proxy of a ProxyPool.
+type ProxyStrategy int
+
+const (
+	// RoundRobin cycles through the proxies in order.
+	RoundRobin ProxyStrategy = iota
+	// Random picks a random proxy for every task.
+	Random
+	// StickyPerSite keeps using the same proxy for a site until it is banned.
+	StickyPerSite
+)
+
+const (
+	// DefaultProxyCooldown is how long a banned proxy is quarantined.
+	DefaultProxyCooldown = 10 * time.Minute
+	// DefaultProxyAttempts is how many proxies are tried per task.
+	DefaultProxyAttempts = 3
+)
+
+// ProxyPool hands out proxies to proxy-capable tasks and quarantines
+// proxies that the API reports as ProxyBanned.
+type ProxyPool struct {
+	strategy ProxyStrategy
+	cooldown time.Duration
+	attempts int
+
+	mu      sync.Mutex
+	proxies []Proxy
+	next    int
+	banned  map[Proxy]time.Time
+	sticky  map[string]Proxy
+}
+
+// ProxyPoolOption configures a ProxyPool.
+type ProxyPoolOption func(*ProxyPool)
+
+// WithProxyStrategy sets the selection strategy (RoundRobin by default).
+func WithProxyStrategy(strategy ProxyStrategy) ProxyPoolOption {
+	return func(p *ProxyPool) {
+		p.strategy = strategy
+	}
+}
+
+// WithProxyCooldown sets how long a banned proxy is quarantined.
+func WithProxyCooldown(d time.Duration) ProxyPoolOption {
+	return func(p *ProxyPool) {
+		p.cooldown = d
+	}
+}
+
+// WithProxyAttempts sets how many proxies are tried per task before giving up.
+func WithProxyAttempts(n int) ProxyPoolOption {
+	return func(p *ProxyPool) {
+		p.attempts = n
+	}
+}
+
+// NewProxyPool creates a pool of the given proxies.
+func NewProxyPool(proxies []Proxy, opts ...ProxyPoolOption) *ProxyPool {
+	p := &ProxyPool{
+		strategy: RoundRobin,
+		cooldown: DefaultProxyCooldown,
+		attempts: DefaultProxyAttempts,
+		proxies:  append([]Proxy(nil), proxies...),
+		banned:   make(map[Proxy]time.Time),
+		sticky:   make(map[string]Proxy),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Next returns the proxy to use for a task on the given site.
+// It returns ErrNoProxy if every proxy is quarantined.
+func (p *ProxyPool) Next(site string) (Proxy, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	available := make([]Proxy, 0, len(p.proxies))
+	for _, proxy := range p.proxies {
+		if until, ok := p.banned[proxy]; ok {
+			if now.Before(until) {
+				continue
+			}
+			delete(p.banned, proxy)
+		}
+		available = append(available, proxy)
+	}
+	if len(available) == 0 {
+		return Proxy{}, ErrNoProxy
+	}
+
+	switch p.strategy {
+	case Random:
+		return available[rand.N(len(available))], nil
+	case StickyPerSite:
+		host := siteHost(site)
+		if proxy, ok := p.sticky[host]; ok {
+			if _, banned := p.banned[proxy]; !banned {
+				return proxy, nil
+			}
+		}
+		proxy := available[p.next%len(available)]
+		p.next++
+		p.sticky[host] = proxy
+		return proxy, nil
+	default:
+		proxy := available[p.next%len(available)]
+		p.next++
+		return proxy, nil
+	}
+}
+
+// Ban quarantines the proxy for the pool cooldown.
+func (p *ProxyPool) Ban(proxy Proxy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.banned[proxy] = time.Now().Add(p.cooldown)
+	for host, sticky := range p.sticky {
+		if sticky == proxy {
+			delete(p.sticky, host)
+		}
+	}
+}
+
+// Available returns the number of proxies that are not quarantined.
+func (p *ProxyPool) Available() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	n := 0
+	for _, proxy := range p.proxies {
+		if until, ok := p.banned[proxy]; !ok || !now.Before(until) {
+			n++
+		}
+	}
+	return n
+}
+
+// siteHost returns the host of a site URL, or the URL itself if it cannot be parsed.
+func siteHost(site string) string {
+	if u, err := url.Parse(site); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return site
+}
+
+// runWithProxies solves a proxy-capable task through the session proxy pool,
+// moving on to another proxy when the API reports the current one as banned.
+// If the pool runs out of proxies, the ProxyBanned error is joined to ErrNoProxy.
+func (s *Session) runWithProxies(ctx context.Context, task ProxyTask, wait bool) (*Result, trace, error) {
+	pool := s.proxies
+	var tr trace
+	var banErr error
+	for attempt := 1; ; attempt++ {
+		proxy, err := pool.Next(task.SiteURL())
+		if err != nil {
+			if banErr != nil {
+				err = errors.Join(err, banErr)
+			}
+			return nil, tr, err
+		}
+		var res *Result
+		res, tr, err = s.runOnce(ctx, task.WithProxy(proxy), wait)
+		if !errors.Is(err, ProxyBanned) {
+			return res, tr, err
+		}
+		pool.Ban(proxy)
+		banErr = err
+		if attempt >= pool.attempts {
+			return nil, tr, err
+		}
+	}
+}
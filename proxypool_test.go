@@ -0,0 +1,139 @@
+package capsolver_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nukilabs/capsolver"
+	"github.com/nukilabs/capsolver/capsolvertest"
+)
+
+var proxies = []capsolver.Proxy{
+	capsolver.MustParseProxy("http://127.0.0.1:8001"),
+	capsolver.MustParseProxy("http://127.0.0.1:8002"),
+	capsolver.MustParseProxy("http://127.0.0.1:8003"),
+}
+
+// nextProxy returns the next proxy of the pool for the site.
+func nextProxy(t *testing.T, pool *capsolver.ProxyPool, site string) capsolver.Proxy {
+	t.Helper()
+	proxy, err := pool.Next(site)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proxy
+}
+
+func TestProxyPoolRoundRobin(t *testing.T) {
+	pool := capsolver.NewProxyPool(proxies)
+	for i := range 6 {
+		if got := nextProxy(t, pool, "https://example.com"); got != proxies[i%3] {
+			t.Errorf("Next() #%d = %v, want %v", i, got, proxies[i%3])
+		}
+	}
+	pool.Ban(proxies[1])
+	for range 4 {
+		if got := nextProxy(t, pool, "https://example.com"); got == proxies[1] {
+			t.Errorf("Next() = banned proxy %v", got)
+		}
+	}
+}
+
+func TestProxyPoolRandom(t *testing.T) {
+	pool := capsolver.NewProxyPool(proxies, capsolver.WithProxyStrategy(capsolver.Random))
+	pool.Ban(proxies[0])
+	seen := make(map[capsolver.Proxy]bool)
+	for range 100 {
+		seen[nextProxy(t, pool, "https://example.com")] = true
+	}
+	if len(seen) != 2 || seen[proxies[0]] {
+		t.Errorf("picked %v, want both unbanned proxies", seen)
+	}
+}
+
+func TestProxyPoolStickyPerSite(t *testing.T) {
+	pool := capsolver.NewProxyPool(proxies, capsolver.WithProxyStrategy(capsolver.StickyPerSite))
+	a := nextProxy(t, pool, "https://a.example.com/login")
+	b := nextProxy(t, pool, "https://b.example.com")
+	if a == b {
+		t.Errorf("both sites got %v", a)
+	}
+	for range 3 {
+		if got := nextProxy(t, pool, "https://a.example.com/other"); got != a {
+			t.Errorf("Next(a) = %v, want %v", got, a)
+		}
+	}
+	pool.Ban(a)
+	moved := nextProxy(t, pool, "https://a.example.com")
+	if moved == a {
+		t.Errorf("Next(a) = banned proxy %v", a)
+	}
+	if got := nextProxy(t, pool, "https://a.example.com"); got != moved {
+		t.Errorf("Next(a) = %v, want it to stick to %v", got, moved)
+	}
+	if got := nextProxy(t, pool, "https://b.example.com"); got != b {
+		t.Errorf("Next(b) = %v, want %v", got, b)
+	}
+}
+
+func TestProxyPoolCooldown(t *testing.T) {
+	pool := capsolver.NewProxyPool(proxies[:2], capsolver.WithProxyCooldown(20*time.Millisecond))
+	pool.Ban(proxies[0])
+	pool.Ban(proxies[1])
+	if n := pool.Available(); n != 0 {
+		t.Errorf("Available() = %d, want 0", n)
+	}
+	if _, err := pool.Next("https://example.com"); !errors.Is(err, capsolver.ErrNoProxy) {
+		t.Fatalf("err = %v, want %v", err, capsolver.ErrNoProxy)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if n := pool.Available(); n != 2 {
+		t.Errorf("Available() = %d after the cooldown, want 2", n)
+	}
+	nextProxy(t, pool, "https://example.com")
+}
+
+func TestProxyPoolRetry(t *testing.T) {
+	task := capsolver.ReCaptchaV2Task{WebsiteURL: "https://example.com", WebsiteKey: "key"}
+	tests := []struct {
+		name     string
+		proxies  int
+		attempts int
+		requests int
+		noProxy  bool
+	}{
+		{"attempts", 3, 2, 2, false},
+		{"out of proxies", 2, 3, 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := capsolvertest.NewServer(t)
+			srv.Handle(task.WithProxy(proxies[0]).TaskType(), capsolvertest.Behavior{Error: capsolver.ProxyBanned})
+			pool := capsolver.NewProxyPool(proxies[:tt.proxies], capsolver.WithProxyAttempts(tt.attempts))
+			s := srv.Session(capsolver.WithProxyPool(pool))
+
+			_, err := s.SolveReCaptchaV2Context(context.Background(), task)
+			if !errors.Is(err, capsolver.ProxyBanned) {
+				t.Errorf("err = %v, want %s", err, capsolver.ProxyBanned)
+			}
+			if errors.Is(err, capsolver.ErrNoProxy) != tt.noProxy {
+				t.Errorf("err = %v, want ErrNoProxy: %v", err, tt.noProxy)
+			}
+			if n := countRequests(srv, "/createTask"); n != tt.requests {
+				t.Errorf("createTask requests = %d, want %d", n, tt.requests)
+			}
+			used := make(map[any]bool)
+			for _, task := range srv.Tasks(task.WithProxy(proxies[0]).TaskType()) {
+				used[task["proxy"]] = true
+			}
+			if len(used) != tt.requests {
+				t.Errorf("tried proxies %v, want %d different ones", used, tt.requests)
+			}
+			if n := pool.Available(); n != tt.proxies-tt.requests {
+				t.Errorf("Available() = %d, want %d", n, tt.proxies-tt.requests)
+			}
+		})
+	}
+}
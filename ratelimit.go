@@ -0,0 +1,102 @@
+package capsolver
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimitStats reports the client-side throttling of an endpoint.
+type RateLimitStats struct {
+	// Rate is the current rate in requests per second, lowered after RateLimit errors.
+	Rate float64
+	// Throttled is the number of requests that had to wait for the limiter.
+	Throttled int64
+	// WaitTime is the total time requests spent waiting for the limiter.
+	WaitTime time.Duration
+}
+
+// rateLimiter is a token bucket that halves its rate whenever the API
+// reports RateLimit and slowly recovers to the configured rate on success.
+type rateLimiter struct {
+	mu     sync.Mutex
+	base   float64
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	stats  RateLimitStats
+}
+
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		base:   rps,
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait takes a token, waiting for one to become available if necessary.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	l.tokens--
+	var d time.Duration
+	if l.tokens < 0 {
+		d = time.Duration(-l.tokens / l.rate * float64(time.Second))
+		l.stats.Throttled++
+		l.stats.WaitTime += d
+	}
+	l.mu.Unlock()
+
+	if err := sleep(ctx, d); err != nil {
+		// Give the reserved token back.
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// slowDown halves the rate, down to a sixteenth of the configured rate.
+func (l *rateLimiter) slowDown() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = max(l.rate/2, l.base/16)
+}
+
+// speedUp raises the rate by a twentieth of the configured rate, up to the configured rate.
+func (l *rateLimiter) speedUp() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = min(l.rate+l.base/20, l.base)
+}
+
+func (l *rateLimiter) snapshot() RateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats := l.stats
+	stats.Rate = l.rate
+	return stats
+}
+
+// RateLimitStats returns the throttling statistics of the rate-limited
+// endpoints, keyed by endpoint (e.g. "/createTask").
+func (s *Session) RateLimitStats() map[string]RateLimitStats {
+	stats := make(map[string]RateLimitStats, len(s.limiters))
+	for endpoint, l := range s.limiters {
+		stats[endpoint] = l.snapshot()
+	}
+	return stats
+}
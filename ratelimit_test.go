@@ -0,0 +1,104 @@
+package capsolver_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nukilabs/capsolver"
+	"github.com/nukilabs/capsolver/capsolvertest"
+)
+
+var image = capsolver.ImageToTextTask{Body: "aW1hZ2U="}
+
+func TestCreateTaskRate(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(image.TaskType(), capsolvertest.Behavior{Solution: capsolver.ImageToTextSolution{Text: "abc"}, Sync: true})
+	s := srv.Session(capsolver.WithCreateTaskRate(100, 2))
+	ctx := context.Background()
+
+	// After the burst of 2, every request waits for a token refilled at 10ms.
+	start := time.Now()
+	for range 6 {
+		if _, err := capsolver.SolveTask[capsolver.ImageToTextSolution](ctx, s, image); err != nil {
+			t.Fatal(err)
+		}
+	}
+	elapsed := time.Since(start)
+	if elapsed < 40*time.Millisecond {
+		t.Errorf("6 requests took %v, want at least 40ms at 100 rps with burst 2", elapsed)
+	}
+
+	stats, ok := s.RateLimitStats()["/createTask"]
+	if !ok {
+		t.Fatal("no stats for /createTask")
+	}
+	if stats.Rate != 100 {
+		t.Errorf("Rate = %v, want 100", stats.Rate)
+	}
+	if stats.Throttled < 1 || stats.Throttled > 4 {
+		t.Errorf("Throttled = %d, want 1 to 4", stats.Throttled)
+	}
+	if stats.WaitTime <= 0 || stats.WaitTime > elapsed {
+		t.Errorf("WaitTime = %v, want between 0 and %v", stats.WaitTime, elapsed)
+	}
+	if _, ok := s.RateLimitStats()["/getTaskResult"]; ok {
+		t.Error("stats for /getTaskResult without a limit")
+	}
+}
+
+func TestCreateTaskRateCancel(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(image.TaskType(), capsolvertest.Behavior{Solution: capsolver.ImageToTextSolution{Text: "abc"}, Sync: true})
+	s := srv.Session(capsolver.WithCreateTaskRate(1, 1))
+
+	if _, err := capsolver.SolveTask[capsolver.ImageToTextSolution](context.Background(), s, image); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := capsolver.SolveTask[capsolver.ImageToTextSolution](ctx, s, image); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if n := countRequests(srv, "/createTask"); n != 1 {
+		t.Errorf("createTask requests = %d, want 1", n)
+	}
+}
+
+func TestRateLimitAdapts(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(image.TaskType(), capsolvertest.Behavior{Error: capsolver.RateLimit})
+	s := srv.Session(capsolver.WithRetryPolicy(capsolver.NoRetry), capsolver.WithCreateTaskRate(160, 100))
+	ctx := context.Background()
+	rate := func() float64 { return s.RateLimitStats()["/createTask"].Rate }
+
+	// Every RateLimit error halves the rate, down to a sixteenth.
+	for _, want := range []float64{80, 40, 20, 10, 10} {
+		if _, err := capsolver.SolveTask[capsolver.ImageToTextSolution](ctx, s, image); !errors.Is(err, capsolver.RateLimit) {
+			t.Fatalf("err = %v, want %s", err, capsolver.RateLimit)
+		}
+		if got := rate(); got != want {
+			t.Fatalf("Rate = %v, want %v", got, want)
+		}
+	}
+
+	// Every success raises it by a twentieth, up to the configured rate.
+	srv.Handle(image.TaskType(), capsolvertest.Behavior{Solution: capsolver.ImageToTextSolution{Text: "abc"}, Sync: true})
+	for _, want := range []float64{18, 26, 34} {
+		if _, err := capsolver.SolveTask[capsolver.ImageToTextSolution](ctx, s, image); err != nil {
+			t.Fatal(err)
+		}
+		if got := rate(); got != want {
+			t.Fatalf("Rate = %v, want %v", got, want)
+		}
+	}
+	for range 20 {
+		if _, err := capsolver.SolveTask[capsolver.ImageToTextSolution](ctx, s, image); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := rate(); got != 160 {
+		t.Errorf("Rate = %v, want 160", got)
+	}
+}
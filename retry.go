@@ -0,0 +1,147 @@
+package capsolver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"net/url"
+	"time"
+)
+
+// RetryPolicy controls how failed createTask and getTaskResult requests are retried.
+// Only transient failures are retried: ServiceUnavailable, RateLimit, 5xx responses and network errors.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts per request, including the first one.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry; it doubles after every attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between two attempts.
+	MaxDelay time.Duration
+}
+
+// DefaultRetryPolicy is the retry policy used by New.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// NoRetry disables retries.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+const (
+	// keyTempBlockedPause is how long the API blocks a key after KeyTempBlocked.
+	keyTempBlockedPause = 5 * time.Minute
+	// ipBannedPause is how long the API blocks an IP after IPBanned.
+	ipBannedPause = 30 * time.Minute
+)
+
+// backoff returns the delay before the given retry (1-based) with jitter applied.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < retry && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	// Equal jitter: half fixed, half random.
+	return d/2 + rand.N(d/2+1)
+}
+
+// do sends the request and retries transient failures according to the retry policy.
+// API errors are returned as Error.
+func (s *Session) do(ctx context.Context, endpoint string, payload Payload) (*Result, error) {
+	for attempt := 1; ; attempt++ {
+		if err := s.waitPause(ctx); err != nil {
+			return nil, err
+		}
+		res, err := s.post(ctx, endpoint, payload)
+		if err == nil && res.Error.ID != 0 {
+			err = res.Error
+		}
+		if err == nil {
+			return res, nil
+		}
+		s.pauseOn(ctx, err)
+		if s.balanceHook != nil && errors.Is(err, ZeroBalance) {
+			s.balanceHook(ctx, Balance{})
+		}
+		if attempt >= s.retry.MaxAttempts || !retryable(err) {
+			return nil, err
+		}
+		delay := s.retry.backoff(attempt)
+		s.logger.LogAttrs(ctx, slog.LevelWarn, "capsolver request retry", slog.String("endpoint", endpoint), slog.String("taskId", payload.TaskID), slog.Int("attempt", attempt), slog.Duration("delay", delay), slog.Any("error", err))
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryable reports whether err is a transient failure worth sending the same request again.
+func retryable(err error) bool {
+	var apiErr Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == ServiceUnavailable || apiErr.Code == RateLimit
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500 || httpErr.StatusCode == 429
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// pauseOn blocks the session when the API reports a temporary key or IP ban.
+func (s *Session) pauseOn(ctx context.Context, err error) {
+	var apiErr Error
+	if !errors.As(err, &apiErr) {
+		return
+	}
+	var d time.Duration
+	switch apiErr.Code {
+	case KeyTempBlocked:
+		d = keyTempBlockedPause
+	case IPBanned:
+		d = ipBannedPause
+	default:
+		return
+	}
+	until := time.Now().Add(d)
+	s.mu.Lock()
+	if until.After(s.pausedUntil) {
+		s.pausedUntil = until
+	}
+	s.mu.Unlock()
+	s.logger.LogAttrs(ctx, slog.LevelError, "capsolver session paused", slog.String("errorCode", string(apiErr.Code)), slog.Time("until", until))
+}
+
+// waitPause waits until the session is no longer paused.
+func (s *Session) waitPause(ctx context.Context) error {
+	s.mu.Lock()
+	until := s.pausedUntil
+	s.mu.Unlock()
+	return sleep(ctx, time.Until(until))
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
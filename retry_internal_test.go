@@ -0,0 +1,61 @@
+package capsolver
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}
+	for _, tt := range []struct {
+		retry int
+		want  time.Duration
+	}{
+		{1, 10 * time.Millisecond},
+		{2, 20 * time.Millisecond},
+		{3, 40 * time.Millisecond},
+		{4, 50 * time.Millisecond},
+		{100, 50 * time.Millisecond},
+	} {
+		for range 100 {
+			if d := p.backoff(tt.retry); d < tt.want/2 || d > tt.want {
+				t.Fatalf("backoff(%d) = %v, want between %v and %v", tt.retry, d, tt.want/2, tt.want)
+			}
+		}
+	}
+	if d := NoRetry.backoff(1); d != 0 {
+		t.Errorf("NoRetry.backoff(1) = %v, want 0", d)
+	}
+}
+
+func TestPauseOn(t *testing.T) {
+	ctx := context.Background()
+	s := New("key")
+
+	s.pauseOn(ctx, Error{Code: InvalidTaskData})
+	if !s.pausedUntil.IsZero() {
+		t.Fatalf("paused until %v on %s", s.pausedUntil, InvalidTaskData)
+	}
+
+	start := time.Now()
+	s.pauseOn(ctx, Error{Code: IPBanned})
+	if d := s.pausedUntil.Sub(start); d < ipBannedPause || d > ipBannedPause+time.Second {
+		t.Errorf("paused for %v after %s, want %v", d, IPBanned, ipBannedPause)
+	}
+	// A shorter pause does not cut a longer one short.
+	until := s.pausedUntil
+	s.pauseOn(ctx, Error{Code: KeyTempBlocked})
+	if !s.pausedUntil.Equal(until) {
+		t.Errorf("paused until %v after %s, want %v", s.pausedUntil, KeyTempBlocked, until)
+	}
+
+	s.pausedUntil = time.Now().Add(10 * time.Millisecond)
+	start = time.Now()
+	if err := s.waitPause(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Errorf("waitPause returned after %v, want at least 10ms", elapsed)
+	}
+}
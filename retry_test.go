@@ -0,0 +1,113 @@
+package capsolver_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nukilabs/capsolver"
+	"github.com/nukilabs/capsolver/capsolvertest"
+)
+
+func TestRetry(t *testing.T) {
+	tests := []struct {
+		name       string
+		behavior   capsolvertest.Behavior
+		wantErr    error
+		wantStatus int
+		requests   int
+	}{
+		{"ServiceUnavailable", capsolvertest.Behavior{Error: capsolver.ServiceUnavailable}, capsolver.ServiceUnavailable, 0, 4},
+		{"RateLimit", capsolvertest.Behavior{Error: capsolver.RateLimit}, capsolver.RateLimit, 0, 4},
+		{"InvalidTaskData", capsolvertest.Behavior{Error: capsolver.InvalidTaskData}, capsolver.InvalidTaskData, 0, 1},
+		{"HTTP500", capsolvertest.Behavior{HTTPStatus: 500}, nil, 500, 4},
+		{"HTTP400", capsolvertest.Behavior{HTTPStatus: 400}, nil, 400, 1},
+		{"Recovered", capsolvertest.Behavior{Solution: capsolver.ImageToTextSolution{Text: "abc"}, Sync: true, HTTPStatus: 503, HTTPFailures: 2}, nil, 0, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := capsolvertest.NewServer(t)
+			srv.Handle(image.TaskType(), tt.behavior)
+			_, err := capsolver.SolveTask[capsolver.ImageToTextSolution](context.Background(), srv.Session(), image)
+			var httpErr *capsolver.HTTPError
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("err = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantStatus != 0:
+				if !errors.As(err, &httpErr) || httpErr.StatusCode != tt.wantStatus {
+					t.Errorf("err = %v, want HTTP %d", err, tt.wantStatus)
+				}
+			case err != nil:
+				t.Errorf("err = %v, want nil", err)
+			}
+			if n := countRequests(srv, "/createTask"); n != tt.requests {
+				t.Errorf("createTask requests = %d, want %d", n, tt.requests)
+			}
+		})
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(image.TaskType(), capsolvertest.Behavior{Error: capsolver.ServiceUnavailable})
+	s := srv.Session(capsolver.WithRetryPolicy(capsolver.RetryPolicy{MaxAttempts: 3, BaseDelay: 20 * time.Millisecond, MaxDelay: 30 * time.Millisecond}))
+
+	// The retries wait 10-20ms and, capped at MaxDelay, 15-30ms.
+	start := time.Now()
+	if _, err := capsolver.SolveTask[capsolver.ImageToTextSolution](context.Background(), s, image); !errors.Is(err, capsolver.ServiceUnavailable) {
+		t.Fatalf("err = %v, want %s", err, capsolver.ServiceUnavailable)
+	}
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Errorf("3 attempts took %v, want at least 25ms", elapsed)
+	}
+	if n := countRequests(srv, "/createTask"); n != 3 {
+		t.Errorf("createTask requests = %d, want 3", n)
+	}
+}
+
+func TestRetryCancel(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(image.TaskType(), capsolvertest.Behavior{Error: capsolver.ServiceUnavailable})
+	s := srv.Session(capsolver.WithRetryPolicy(capsolver.RetryPolicy{MaxAttempts: 10, BaseDelay: time.Second}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := capsolver.SolveTask[capsolver.ImageToTextSolution](ctx, s, image); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if n := countRequests(srv, "/createTask"); n != 1 {
+		t.Errorf("createTask requests = %d, want 1", n)
+	}
+}
+
+func TestPause(t *testing.T) {
+	for _, code := range []capsolver.ErrorCode{capsolver.KeyTempBlocked, capsolver.IPBanned} {
+		t.Run(string(code), func(t *testing.T) {
+			srv := capsolvertest.NewServer(t)
+			srv.Handle(image.TaskType(), capsolvertest.Behavior{Error: code})
+			s := srv.Session()
+
+			if _, err := capsolver.SolveTask[capsolver.ImageToTextSolution](context.Background(), s, image); !errors.Is(err, code) {
+				t.Fatalf("err = %v, want %s", err, code)
+			}
+
+			// The session is paused for minutes, so the next request waits until ctx is done.
+			srv.Handle(image.TaskType(), capsolvertest.Behavior{Solution: capsolver.ImageToTextSolution{Text: "abc"}, Sync: true})
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			start := time.Now()
+			if _, err := capsolver.SolveTask[capsolver.ImageToTextSolution](ctx, s, image); !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("err = %v, want %v", err, context.DeadlineExceeded)
+			}
+			if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+				t.Errorf("paused request returned after %v, want at least 20ms", elapsed)
+			}
+			if n := countRequests(srv, "/createTask"); n != 1 {
+				t.Errorf("createTask requests = %d, want 1", n)
+			}
+		})
+	}
+}
@@ -1,86 +1,299 @@
-package capsolver
-
-import (
-	"bytes"
-	"encoding/json"
-	"net/http"
-	"time"
-)
-
-const (
-	ApiURL     = "https://api.capsolver.com"
-	AppID      = "D3119ABC-FF91-42EF-9F18-C4CE4B259E52"
-	MaxRetries = 120
-)
-
-type Session struct {
-	key    string
-	client *http.Client
-}
-
-func New(key string) *Session {
-	return &Session{
-		key:    key,
-		client: &http.Client{},
-	}
-}
-
-func (s *Session) post(endpoint string, payload Payload) (*Result, error) {
-	payload.ClientKey = s.key
-	payload.AppID = AppID
-
-	data, err := json.Marshal(payload)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest(http.MethodPost, ApiURL+endpoint, bytes.NewBuffer(data))
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Add("Content-Type", "application/json")
-
-	res, err := s.client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	var result Result
-	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return nil, err
-	}
-
-	return &result, nil
-}
-
-func (s *Session) createTask(task any) (*Result, error) {
-	return s.post("/createTask", Payload{Task: task})
-}
-
-func (s *Session) getTaskResult(taskID string) (*Result, error) {
-	return s.post("/getTaskResult", Payload{TaskID: taskID})
-}
-
-func (s *Session) Solve(task any) (*Result, error) {
-	res, err := s.createTask(task)
-	if err != nil {
-		return nil, err
-	}
-	if res.Error.ID == 1 {
-		return nil, res.Error
-	}
-	for i := 0; res.Status != StatusReady && i < MaxRetries; i++ {
-		time.Sleep(3 * time.Second)
-
-		res, err = s.getTaskResult(res.TaskId)
-		if err != nil {
-			return nil, err
-		}
-		if res.Error.ID == 1 {
-			return nil, res.Error
-		}
-	}
-	return res, nil
-}
+package capsolver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const (
+	ApiURL       = "https://api.capsolver.com"
+	AppID        = "D3119ABC-FF91-42EF-9F18-C4CE4B259E52"
+	MaxRetries   = 120
+	PollInterval = 3 * time.Second
+)
+
+type Session struct {
+	key          string
+	baseURL      string
+	appID        string
+	client       *http.Client
+	pollInterval time.Duration
+	initialDelay time.Duration
+	maxPolls     int
+	retry        RetryPolicy
+
+	balanceThreshold float64
+	balanceHook      BalanceHook
+	proxies          *ProxyPool
+	poller           *poller
+	limiters         map[string]*rateLimiter
+	logger           *slog.Logger
+	solveHook        SolveHook
+	middlewares      []Middleware
+	handler          Handler
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+// New creates a session for the given client key.
+// Without options it talks to ApiURL and polls every PollInterval up to MaxRetries times.
+func New(key string, opts ...Option) *Session {
+	s := &Session{
+		key:          key,
+		baseURL:      ApiURL,
+		appID:        AppID,
+		client:       &http.Client{},
+		pollInterval: PollInterval,
+		initialDelay: PollInterval,
+		maxPolls:     MaxRetries,
+		retry:        DefaultRetryPolicy,
+		logger:       slog.New(slog.DiscardHandler),
+	}
+	s.poller = newPoller(s)
+	s.limiters = make(map[string]*rateLimiter)
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.buildHandler()
+	return s
+}
+
+const (
+	endpointCreateTask    = "/createTask"
+	endpointGetTaskResult = "/getTaskResult"
+	endpointGetBalance    = "/getBalance"
+	endpointFeedbackTask  = "/feedbackTask"
+)
+
+// post sends the request through the middleware chain.
+func (s *Session) post(ctx context.Context, endpoint string, payload Payload) (*Result, error) {
+	payload.ClientKey = s.key
+	payload.AppID = s.appID
+	return s.handler(ctx, endpoint, payload)
+}
+
+// send is the innermost Handler and performs the HTTP request.
+func (s *Session) send(ctx context.Context, endpoint string, payload Payload) (*Result, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+endpoint, bytes.NewBuffer(data))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+
+	limiter := s.limiters[endpoint]
+	if limiter != nil {
+		if err := limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("[capsolver] %s: %w", endpoint, err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("[capsolver] %s: %w", endpoint, err)
+	}
+
+	var result Result
+	jsonErr := json.Unmarshal(body, &result)
+	if limiter != nil && jsonErr == nil {
+		if result.Error.Code == RateLimit {
+			limiter.slowDown()
+		} else if result.Error.ID == 0 {
+			limiter.speedUp()
+		}
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		// Some API errors come with a 4xx status but still carry a JSON error body.
+		if jsonErr == nil && result.Error.ID != 0 {
+			result.Raw = body
+			return &result, nil
+		}
+		return nil, newHTTPError(endpoint, res, body)
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, fmt.Errorf("[capsolver] %s: %w", endpoint, ErrEmptyResponse)
+	}
+	if jsonErr != nil {
+		return nil, fmt.Errorf("[capsolver] %s: %w: %w: %s", endpoint, ErrInvalidResponse, jsonErr, truncate(body, maxErrorBody))
+	}
+
+	result.Raw = body
+	return &result, nil
+}
+
+func (s *Session) createTask(ctx context.Context, task any) (*Result, error) {
+	if t, ok := task.(Task); ok {
+		data, err := encodeTask(t)
+		if err != nil {
+			return nil, err
+		}
+		task = data
+	}
+	return s.do(ctx, endpointCreateTask, Payload{Task: task})
+}
+
+func (s *Session) getTaskResult(ctx context.Context, taskID string) (*Result, error) {
+	return s.do(ctx, endpointGetTaskResult, Payload{TaskID: taskID})
+}
+
+// Solve submits the task and polls until the solution is ready.
+// The task is either a Task or any value that encodes to a JSON object with a "type" field.
+func (s *Session) Solve(task any) (*Result, error) {
+	return s.SolveContext(context.Background(), task)
+}
+
+// SolveContext is like Solve but aborts the requests and the polling
+// as soon as ctx is cancelled or its deadline passes.
+//
+// Tasks that createTask answers synchronously (e.g. image recognition) are
+// returned right away, all others are polled with getTaskResult.
+// If the task is still not ready after the maximum number of polls,
+// a *PollTimeoutError is returned and the task can be collected later with Wait.
+func (s *Session) SolveContext(ctx context.Context, task any) (*Result, error) {
+	res, _, err := s.run(ctx, task, true)
+	return res, err
+}
+
+// run creates the task and, if wait is set, waits for its solution, recording the timings in a trace.
+// Without wait it returns the createTask result, which is only ready for tasks solved synchronously.
+func (s *Session) run(ctx context.Context, task any, wait bool) (*Result, trace, error) {
+	if t, ok := task.(ProxyTask); ok && s.proxies != nil && t.TaskProxy().IsZero() {
+		return s.runWithProxies(ctx, t, wait)
+	}
+	return s.runOnce(ctx, task, wait)
+}
+
+// runOnce creates the task and, if wait is set, waits for its solution.
+func (s *Session) runOnce(ctx context.Context, task any, wait bool) (res *Result, tr trace, err error) {
+	start := time.Now()
+	typ := taskType(task)
+	tr.typ = typ
+	if s.solveHook != nil {
+		ev := SolveEvent{TaskType: typ}
+		if t, ok := task.(ProxyTask); ok {
+			ev.Proxy = !t.TaskProxy().IsZero()
+		}
+		var done func(SolveEvent)
+		ctx, done = s.solveHook(ctx, ev)
+		defer func() {
+			ev.TaskID = tr.id
+			ev.Polls = tr.polls
+			ev.Err = err
+			done(ev)
+		}()
+	}
+	res, err = s.createTask(ctx, task)
+	if err != nil {
+		err = annotate(err, "", typ)
+		s.logger.LogAttrs(ctx, slog.LevelWarn, "capsolver task failed", slog.String("taskType", typ), slog.Duration("elapsed", time.Since(start)), slog.Any("error", err))
+		return nil, tr, err
+	}
+	tr.created = time.Now()
+	tr.id = res.TaskId
+	id := res.TaskId
+	s.logger.LogAttrs(ctx, slog.LevelDebug, "capsolver task created", slog.String("taskType", typ), slog.String("taskId", id), slog.String("status", string(res.Status)))
+	if !res.ready() {
+		if id == "" {
+			err = fmt.Errorf("%w (%s)", ErrNoTaskID, typ)
+			s.logger.LogAttrs(ctx, slog.LevelWarn, "capsolver task failed", slog.String("taskType", typ), slog.Duration("elapsed", time.Since(start)), slog.Any("error", err))
+			return nil, tr, err
+		}
+		if !wait {
+			return res, tr, nil
+		}
+		res, tr.polls, err = s.wait(ctx, id, typ, s.initialDelay, s.maxPolls)
+		if err != nil {
+			s.logger.LogAttrs(ctx, slog.LevelWarn, "capsolver task failed", slog.String("taskType", typ), slog.String("taskId", id), slog.Duration("elapsed", time.Since(start)), slog.Int("polls", tr.polls), slog.Any("error", err))
+			return nil, tr, err
+		}
+	}
+	tr.ready = time.Now()
+	s.logger.LogAttrs(ctx, slog.LevelInfo, "capsolver task solved", slog.String("taskType", typ), slog.String("taskId", id), slog.Duration("elapsed", time.Since(start)), slog.Int("polls", tr.polls))
+	return res, tr, nil
+}
+
+// GetTaskResult fetches the current state of a task once, without waiting.
+// Check Result.Status to see whether the solution is ready.
+func (s *Session) GetTaskResult(ctx context.Context, taskID string) (*Result, error) {
+	res, err := s.getTaskResult(ctx, taskID)
+	if err != nil {
+		return nil, annotate(err, taskID, "")
+	}
+	if res.TaskId == "" {
+		res.TaskId = taskID
+	}
+	return res, nil
+}
+
+// Wait polls an already created task until its solution is ready.
+// It is typically used to collect a task after a *PollTimeoutError.
+// It sends at most as many polls as a solve (see WithMaxPolls);
+// use WaitPolls to collect a task with a different budget.
+func (s *Session) Wait(ctx context.Context, taskID string) (*Result, error) {
+	return s.WaitPolls(ctx, taskID, s.maxPolls)
+}
+
+// WaitPolls is like Wait but sends at most maxPolls getTaskResult requests.
+func (s *Session) WaitPolls(ctx context.Context, taskID string, maxPolls int) (*Result, error) {
+	res, _, err := s.wait(ctx, taskID, "", 0, maxPolls)
+	return res, err
+}
+
+// wait polls the task through the shared poller until it is ready or maxPolls
+// polls were sent, sleeping delay before the first poll. It returns the number of polls sent.
+func (s *Session) wait(ctx context.Context, id, typ string, delay time.Duration, maxPolls int) (*Result, int, error) {
+	if maxPolls <= 0 {
+		return nil, 0, &PollTimeoutError{TaskID: id}
+	}
+	return s.poller.wait(ctx, id, typ, delay, maxPolls)
+}
+
+// annotate attaches the task ID and type to API errors.
+func annotate(err error, taskID, taskType string) error {
+	if apiErr, ok := err.(Error); ok {
+		apiErr.TaskID = taskID
+		apiErr.TaskType = taskType
+		return apiErr
+	}
+	return err
+}
+
+// taskType returns the type of a Task, or the "type" of a task struct or map.
+// It returns "" if the task has no type.
+func taskType(task any) string {
+	if t, ok := task.(Task); ok {
+		return t.TaskType()
+	}
+	if m, ok := task.(map[string]any); ok {
+		typ, _ := m["type"].(string)
+		return typ
+	}
+	v := reflect.Indirect(reflect.ValueOf(task))
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	if f := v.FieldByName("Type"); f.IsValid() && f.Kind() == reflect.String {
+		return f.String()
+	}
+	return ""
+}
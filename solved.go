@@ -0,0 +1,38 @@
+package capsolver
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Solved wraps a typed solution together with the metadata of the task that produced it.
+type Solved[S any] struct {
+	// Solution is the typed solution.
+	Solution S
+	// TaskID is the ID of the task, e.g. for Session.ReportResult.
+	TaskID string
+	// Type is the task type sent to the API (e.g. "ReCaptchaV2TaskProxyLess").
+	Type string
+	// CreatedAt is the time createTask returned.
+	CreatedAt time.Time
+	// ReadyAt is the time the solution was received.
+	ReadyAt time.Time
+	// PollCount is the number of getTaskResult requests sent.
+	PollCount int
+	// Raw is the solution as returned by the API.
+	Raw json.RawMessage
+}
+
+// Duration returns the time between task creation and the solution being received.
+func (s *Solved[S]) Duration() time.Duration {
+	return s.ReadyAt.Sub(s.CreatedAt)
+}
+
+// trace records the timings of a single solve.
+type trace struct {
+	id      string
+	typ     string
+	created time.Time
+	ready   time.Time
+	polls   int
+}
@@ -0,0 +1,39 @@
+package capsolver
+
+import "context"
+
+//go:generate moq -out capsolvermock/solver.go -pkg capsolvermock . Solver
+
+// Solver is the set of solve methods implemented by Session.
+// Accept a Solver instead of *Session to swap in a mock or another provider.
+type Solver interface {
+	Solve(task any) (*Result, error)
+	SolveContext(ctx context.Context, task any) (*Result, error)
+	SolveBatch(ctx context.Context, tasks []Task, opts BatchOptions) []BatchResult
+	SolveStream(ctx context.Context, tasks []Task, opts BatchOptions) <-chan BatchResult
+
+	SolveAwsWafClassification(task AwsWafClassificationTask) (*Solved[AwsWafClassificationSolution], error)
+	SolveAwsWafClassificationContext(ctx context.Context, task AwsWafClassificationTask) (*Solved[AwsWafClassificationSolution], error)
+	SolveAntiAwsWaf(task AntiAwsWafTask) (*Solved[AntiAwsWafSolution], error)
+	SolveAntiAwsWafContext(ctx context.Context, task AntiAwsWafTask) (*Solved[AntiAwsWafSolution], error)
+	SolveAntiTurnstile(task AntiTurnstileTask) (*Solved[AntiTurnstileSolution], error)
+	SolveAntiTurnstileContext(ctx context.Context, task AntiTurnstileTask) (*Solved[AntiTurnstileSolution], error)
+	SolveDataDomeSlider(task DataDomeSliderTask) (*Solved[DataDomeSolution], error)
+	SolveDataDomeSliderContext(ctx context.Context, task DataDomeSliderTask) (*Solved[DataDomeSolution], error)
+	SolveGeeTest(task GeeTestTask) (*Solved[GeeTestSolution], error)
+	SolveGeeTestContext(ctx context.Context, task GeeTestTask) (*Solved[GeeTestSolution], error)
+	SolveImageToText(task ImageToTextTask) (*Solved[ImageToTextSolution], error)
+	SolveImageToTextContext(ctx context.Context, task ImageToTextTask) (*Solved[ImageToTextSolution], error)
+	SolveMtCaptcha(task MtCaptchaTask) (*Solved[MtCaptchaSolution], error)
+	SolveMtCaptchaContext(ctx context.Context, task MtCaptchaTask) (*Solved[MtCaptchaSolution], error)
+	SolveReCaptchaV2Classification(task ReCaptchaV2ClassificationTask) (*Solved[ReCaptchaV2ClassificationSolution], error)
+	SolveReCaptchaV2ClassificationContext(ctx context.Context, task ReCaptchaV2ClassificationTask) (*Solved[ReCaptchaV2ClassificationSolution], error)
+	SolveReCaptchaV2(task ReCaptchaV2Task) (*Solved[ReCaptchaV2Solution], error)
+	SolveReCaptchaV2Context(ctx context.Context, task ReCaptchaV2Task) (*Solved[ReCaptchaV2Solution], error)
+	SolveReCaptchaV3(task ReCaptchaV3Task) (*Solved[ReCaptchaV3Solution], error)
+	SolveReCaptchaV3Context(ctx context.Context, task ReCaptchaV3Task) (*Solved[ReCaptchaV3Solution], error)
+	SolveVisionEngine(task VisionEngineTask) (*Solved[VisionEngineSolution], error)
+	SolveVisionEngineContext(ctx context.Context, task VisionEngineTask) (*Solved[VisionEngineSolution], error)
+}
+
+var _ Solver = (*Session)(nil)
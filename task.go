@@ -0,0 +1,115 @@
+package capsolver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Task is implemented by every task that can be sent to createTask.
+// Custom task types only need to implement Task to be solved with SolveTask.
+// Tasks that also implement Validate() error are validated before they are sent.
+type Task interface {
+	// TaskType returns the task type sent to the API (e.g. "ReCaptchaV2TaskProxyLess").
+	// Tasks that accept a proxy return the proxy or proxy-less variant accordingly.
+	TaskType() string
+}
+
+// SolveTask runs the task to completion and decodes the solution into S.
+func SolveTask[S any](ctx context.Context, s *Session, task Task) (*Solved[S], error) {
+	res, tr, err := s.run(ctx, task, true)
+	if err != nil {
+		return nil, err
+	}
+	solved := &Solved[S]{
+		TaskID:    res.TaskId,
+		Type:      tr.typ,
+		CreatedAt: tr.created,
+		ReadyAt:   tr.ready,
+		PollCount: tr.polls,
+		Raw:       res.Solution,
+	}
+	if err := res.Unmarshal(&solved.Solution); err != nil {
+		return nil, err
+	}
+	return solved, nil
+}
+
+// encodeTask marshals the task and sets its "type" field from TaskType.
+func encodeTask(task Task) (json.RawMessage, error) {
+	if v, ok := task.(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("[capsolver] task %T does not encode to a JSON object: %w", task, err)
+	}
+	if fields == nil {
+		fields = make(map[string]json.RawMessage)
+	}
+	fields["type"], _ = json.Marshal(task.TaskType())
+	return json.Marshal(fields)
+}
+
+var registry = struct {
+	sync.RWMutex
+	solutions map[string]reflect.Type
+}{solutions: make(map[string]reflect.Type)}
+
+func init() {
+	RegisterSolution[AwsWafClassificationSolution]("AwsWafClassification")
+	RegisterSolution[AntiAwsWafSolution]("AntiAwsWafTask", "AntiAwsWafTaskProxyLess")
+	RegisterSolution[AntiTurnstileSolution]("AntiTurnstileTaskProxyLess")
+	RegisterSolution[DataDomeSolution]("DatadomeSliderTask")
+	RegisterSolution[GeeTestSolution]("GeeTestTaskProxyLess")
+	RegisterSolution[ImageToTextSolution]("ImageToTextTask")
+	RegisterSolution[MtCaptchaSolution]("MtCaptchaTask", "MtCaptchaTaskProxyLess")
+	RegisterSolution[ReCaptchaV2ClassificationSolution]("ReCaptchaV2Classification")
+	RegisterSolution[ReCaptchaV2Solution]("ReCaptchaV2Task", "ReCaptchaV2TaskProxyLess")
+	RegisterSolution[ReCaptchaV3Solution]("ReCaptchaV3Task", "ReCaptchaV3TaskProxyLess", "ReCaptchaV3EnterpriseTask", "ReCaptchaV3EnterpriseTaskProxyLess")
+	RegisterSolution[VisionEngineSolution]("VisionEngine")
+}
+
+// RegisterSolution associates the given task types with the solution type S,
+// replacing any previous registration.
+func RegisterSolution[S any](taskTypes ...string) {
+	typ := reflect.TypeFor[S]()
+	registry.Lock()
+	defer registry.Unlock()
+	for _, t := range taskTypes {
+		registry.solutions[t] = typ
+	}
+}
+
+// NewSolution returns a pointer to a new zero value of the solution type
+// registered for taskType, or false if none is registered.
+func NewSolution(taskType string) (any, bool) {
+	registry.RLock()
+	typ, ok := registry.solutions[taskType]
+	registry.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return reflect.New(typ).Interface(), true
+}
+
+// DecodeSolution decodes the solution of res into the type registered for taskType.
+// The returned value is a pointer, e.g. *ReCaptchaV2Solution.
+func DecodeSolution(taskType string, res *Result) (any, error) {
+	v, ok := NewSolution(taskType)
+	if !ok {
+		return nil, fmt.Errorf("[capsolver] no solution type registered for %q", taskType)
+	}
+	if err := res.Unmarshal(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,59 @@
+package capsolver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nukilabs/capsolver"
+	"github.com/nukilabs/capsolver/capsolvertest"
+)
+
+type fooTask struct {
+	WebsiteURL string `json:"websiteURL"`
+}
+
+func (fooTask) TaskType() string { return "FooTaskProxyLess" }
+
+type fooSolution struct {
+	Token string `json:"token"`
+}
+
+func TestRegisterSolution(t *testing.T) {
+	capsolver.RegisterSolution[fooSolution]("FooTask", "FooTaskProxyLess")
+	srv := capsolvertest.NewServer(t)
+	srv.Handle("FooTaskProxyLess", capsolvertest.Behavior{Solution: fooSolution{Token: "token"}, Polls: 1})
+	s := srv.Session()
+
+	res, err := s.SolveContext(context.Background(), fooTask{WebsiteURL: "https://example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := capsolver.DecodeSolution("FooTaskProxyLess", res)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sol, ok := v.(*fooSolution)
+	if !ok || sol.Token != "token" {
+		t.Fatalf("DecodeSolution() = %#v, want *fooSolution with the token", v)
+	}
+	if tasks := srv.Tasks("FooTaskProxyLess"); len(tasks) != 1 || tasks[0]["websiteURL"] != "https://example.com" {
+		t.Errorf("tasks = %v, want the encoded fooTask", tasks)
+	}
+
+	if v, ok := capsolver.NewSolution("FooTask"); !ok {
+		t.Error("NewSolution(FooTask) not registered")
+	} else if _, ok := v.(*fooSolution); !ok {
+		t.Errorf("NewSolution(FooTask) = %T, want *fooSolution", v)
+	}
+	if v, ok := capsolver.NewSolution("ReCaptchaV2TaskProxyLess"); !ok {
+		t.Error("NewSolution(ReCaptchaV2TaskProxyLess) not registered")
+	} else if _, ok := v.(*capsolver.ReCaptchaV2Solution); !ok {
+		t.Errorf("NewSolution(ReCaptchaV2TaskProxyLess) = %T, want *ReCaptchaV2Solution", v)
+	}
+	if _, ok := capsolver.NewSolution("UnknownTask"); ok {
+		t.Error("NewSolution(UnknownTask) registered")
+	}
+	if _, err := capsolver.DecodeSolution("UnknownTask", res); err == nil {
+		t.Error("DecodeSolution(UnknownTask) succeeded")
+	}
+}
@@ -0,0 +1,261 @@
+package capsolver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTokenPoolSize is the number of tokens kept solved ahead per key.
+	DefaultTokenPoolSize = 3
+	// DefaultTokenTTL is how long a token is handed out after its creation.
+	// Tokens are accepted by the target sites for about two minutes.
+	DefaultTokenTTL = 110 * time.Second
+)
+
+// DefaultTokenRefillBackoff is the backoff between failed background refills.
+var DefaultTokenRefillBackoff = RetryPolicy{BaseDelay: time.Second, MaxDelay: time.Minute}
+
+// TokenKey identifies the tokens that are interchangeable in a TokenPool.
+type TokenKey struct {
+	Type       string
+	WebsiteURL string
+	WebsiteKey string
+	Action     string
+}
+
+// TokenTask is implemented by tasks whose solution is a short-lived token:
+// ReCaptchaV3Task, AntiTurnstileTask and MtCaptchaTask.
+type TokenTask interface {
+	Task
+	// TokenKey returns the key under which the tokens of this task are pooled.
+	TokenKey() TokenKey
+}
+
+// Token is a solved captcha token.
+type Token struct {
+	// Value is the token to submit to the target site.
+	Value string
+	// UserAgent is the User-Agent used during solving, if returned.
+	UserAgent string
+	// TaskID is the ID of the task that produced the token.
+	TaskID string
+	// CreatedAt is the time the token was created.
+	CreatedAt time.Time
+}
+
+// solveToken solves a token task and extracts its token.
+func solveToken(ctx context.Context, s *Session, task TokenTask) (*Token, error) {
+	switch t := task.(type) {
+	case ReCaptchaV3Task:
+		sol, err := SolveTask[ReCaptchaV3Solution](ctx, s, t)
+		if err != nil {
+			return nil, err
+		}
+		created := sol.ReadyAt
+		if sol.Solution.CreateTime > 0 {
+			created = time.UnixMilli(sol.Solution.CreateTime)
+		}
+		return &Token{Value: sol.Solution.GRecaptchaResponse, UserAgent: sol.Solution.UserAgent, TaskID: sol.TaskID, CreatedAt: created}, nil
+	case AntiTurnstileTask:
+		sol, err := SolveTask[AntiTurnstileSolution](ctx, s, t)
+		if err != nil {
+			return nil, err
+		}
+		return &Token{Value: sol.Solution.Token, UserAgent: sol.Solution.UserAgent, TaskID: sol.TaskID, CreatedAt: sol.ReadyAt}, nil
+	case MtCaptchaTask:
+		sol, err := SolveTask[MtCaptchaSolution](ctx, s, t)
+		if err != nil {
+			return nil, err
+		}
+		return &Token{Value: sol.Solution.Token, TaskID: sol.TaskID, CreatedAt: sol.ReadyAt}, nil
+	}
+	return nil, fmt.Errorf("[capsolver] unsupported token task %T", task)
+}
+
+// TokenPool keeps tokens solved ahead of time so they can be handed out instantly.
+// Every token is handed out at most once and only before its TTL expires.
+type TokenPool struct {
+	s        *Session
+	size     int
+	lowWater int
+	ttl      time.Duration
+	backoff  RetryPolicy
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	queues map[TokenKey]*tokenQueue
+}
+
+type tokenQueue struct {
+	tokens  []*Token
+	filling int
+
+	// err is the error of the last failed refill; it is cleared by a successful one.
+	err error
+	// failures counts the refill failures in a row.
+	failures int
+	// retryAt is the time before which no refill is started after a failure.
+	retryAt time.Time
+}
+
+// TokenPoolOption configures a TokenPool.
+type TokenPoolOption func(*TokenPool)
+
+// WithTokenPoolSize sets the number of tokens kept solved ahead per key.
+func WithTokenPoolSize(n int) TokenPoolOption {
+	return func(p *TokenPool) {
+		p.size = n
+	}
+}
+
+// WithTokenLowWater sets the number of tokens below which the pool refills.
+// It defaults to the pool size, i.e. every token taken is replaced right away.
+func WithTokenLowWater(n int) TokenPoolOption {
+	return func(p *TokenPool) {
+		p.lowWater = n
+	}
+}
+
+// WithTokenTTL sets how long a token is handed out after its creation.
+func WithTokenTTL(d time.Duration) TokenPoolOption {
+	return func(p *TokenPool) {
+		p.ttl = d
+	}
+}
+
+// WithTokenRefillBackoff sets the backoff between failed background refills.
+// Only BaseDelay and MaxDelay of the policy are used.
+func WithTokenRefillBackoff(policy RetryPolicy) TokenPoolOption {
+	return func(p *TokenPool) {
+		p.backoff = policy
+	}
+}
+
+// NewTokenPool creates a token pool that solves its tokens with s.
+// Call Close to stop the background refills.
+func NewTokenPool(s *Session, opts ...TokenPoolOption) *TokenPool {
+	p := &TokenPool{
+		s:        s,
+		size:     DefaultTokenPoolSize,
+		lowWater: -1,
+		ttl:      DefaultTokenTTL,
+		backoff:  DefaultTokenRefillBackoff,
+		queues:   make(map[TokenKey]*tokenQueue),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.lowWater < 0 || p.lowWater > p.size {
+		p.lowWater = p.size
+	}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	return p
+}
+
+// Warm starts filling the pool for the task without taking a token.
+func (p *TokenPool) Warm(task TokenTask) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.refill(task, p.queue(task.TokenKey()))
+}
+
+// Get hands out a pooled token for the task, or solves one right away if the pool is empty.
+// Either way the pool is refilled in the background when it runs low.
+func (p *TokenPool) Get(ctx context.Context, task TokenTask) (*Token, error) {
+	p.mu.Lock()
+	q := p.queue(task.TokenKey())
+	p.expire(q)
+	var tok *Token
+	if len(q.tokens) > 0 {
+		tok = q.tokens[0]
+		q.tokens = q.tokens[1:]
+	}
+	p.refill(task, q)
+	p.mu.Unlock()
+
+	if tok != nil {
+		return tok, nil
+	}
+	return solveToken(ctx, p.s, task)
+}
+
+// Err returns the error of the last background refill for the task, or nil if it succeeded.
+// While refills fail, the pool backs off before starting new ones.
+func (p *TokenPool) Err(task TokenTask) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.queue(task.TokenKey()).err
+}
+
+// Len returns the number of unexpired tokens pooled for the task.
+func (p *TokenPool) Len(task TokenTask) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	q := p.queue(task.TokenKey())
+	p.expire(q)
+	return len(q.tokens)
+}
+
+// Close stops the background refills and waits for them to return.
+func (p *TokenPool) Close() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+// queue returns the queue of the key, creating it if needed. p.mu must be held.
+func (p *TokenPool) queue(key TokenKey) *tokenQueue {
+	q, ok := p.queues[key]
+	if !ok {
+		q = &tokenQueue{}
+		p.queues[key] = q
+	}
+	return q
+}
+
+// expire drops the tokens that are older than the TTL. p.mu must be held.
+// Tokens are queued in completion order, which is not necessarily creation order.
+func (p *TokenPool) expire(q *tokenQueue) {
+	deadline := time.Now().Add(-p.ttl)
+	q.tokens = slices.DeleteFunc(q.tokens, func(tok *Token) bool {
+		return tok.CreatedAt.Before(deadline)
+	})
+}
+
+// refill starts background solves when the queue runs low. p.mu must be held.
+func (p *TokenPool) refill(task TokenTask, q *tokenQueue) {
+	if p.ctx.Err() != nil || len(q.tokens)+q.filling >= p.lowWater || time.Now().Before(q.retryAt) {
+		return
+	}
+	for n := p.size - len(q.tokens) - q.filling; n > 0; n-- {
+		q.filling++
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			tok, err := solveToken(p.ctx, p.s, task)
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			q.filling--
+			switch {
+			case err == nil:
+				q.tokens = append(q.tokens, tok)
+				q.err, q.failures, q.retryAt = nil, 0, time.Time{}
+			case p.ctx.Err() == nil:
+				q.err = err
+				// Concurrent refills that fail together count as one failure.
+				if now := time.Now(); !now.Before(q.retryAt) {
+					q.failures++
+					q.retryAt = now.Add(p.backoff.backoff(q.failures))
+				}
+				p.s.logger.LogAttrs(p.ctx, slog.LevelWarn, "capsolver token refill failed", slog.String("taskType", task.TaskType()), slog.Int("failures", q.failures), slog.Time("retryAt", q.retryAt), slog.Any("error", err))
+			}
+		}()
+	}
+}
@@ -0,0 +1,25 @@
+package capsolver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenPoolExpireUnordered(t *testing.T) {
+	p := NewTokenPool(New("key"), WithTokenTTL(time.Minute))
+	defer p.Close()
+	now := time.Now()
+	fresh1 := &Token{Value: "fresh1", CreatedAt: now}
+	fresh2 := &Token{Value: "fresh2", CreatedAt: now.Add(-30 * time.Second)}
+	q := &tokenQueue{tokens: []*Token{
+		fresh1,
+		{Value: "old1", CreatedAt: now.Add(-2 * time.Minute)},
+		fresh2,
+		{Value: "old2", CreatedAt: now.Add(-time.Hour)},
+	}}
+
+	p.expire(q)
+	if len(q.tokens) != 2 || q.tokens[0] != fresh1 || q.tokens[1] != fresh2 {
+		t.Errorf("tokens after expire = %v, want fresh1 and fresh2", q.tokens)
+	}
+}
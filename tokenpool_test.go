@@ -0,0 +1,89 @@
+package capsolver_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nukilabs/capsolver"
+	"github.com/nukilabs/capsolver/capsolvertest"
+)
+
+// eventually fails the test if cond does not become true within a second.
+func eventually(t *testing.T, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); !cond(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 1s")
+		}
+	}
+}
+
+func TestTokenPoolGet(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}})
+	pool := capsolver.NewTokenPool(srv.Session(), capsolver.WithTokenPoolSize(2))
+	defer pool.Close()
+
+	pool.Warm(turnstile)
+	eventually(t, func() bool { return pool.Len(turnstile) == 2 })
+	tok, err := pool.Get(context.Background(), turnstile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.Value != "token" || tok.TaskID == "" {
+		t.Errorf("token = %+v", tok)
+	}
+	// The taken token is replaced in the background.
+	eventually(t, func() bool { return pool.Len(turnstile) == 2 })
+	if n := countRequests(srv, "/createTask"); n != 3 {
+		t.Errorf("createTask requests = %d, want 3", n)
+	}
+}
+
+func TestTokenPoolRefillError(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Error: capsolver.ZeroBalance})
+	pool := capsolver.NewTokenPool(srv.Session(), capsolver.WithTokenRefillBackoff(capsolver.RetryPolicy{BaseDelay: time.Hour, MaxDelay: time.Hour}))
+	defer pool.Close()
+
+	pool.Warm(turnstile)
+	eventually(t, func() bool {
+		return countRequests(srv, "/createTask") == capsolver.DefaultTokenPoolSize && pool.Err(turnstile) != nil
+	})
+	if err := pool.Err(turnstile); !errors.Is(err, capsolver.ZeroBalance) {
+		t.Fatalf("Err() = %v, want %s", err, capsolver.ZeroBalance)
+	}
+
+	// Get solves right away and reports the error, but does not start new refills while backing off.
+	for range 3 {
+		if _, err := pool.Get(context.Background(), turnstile); !errors.Is(err, capsolver.ZeroBalance) {
+			t.Fatalf("Get() = %v, want %s", err, capsolver.ZeroBalance)
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	if n, want := countRequests(srv, "/createTask"), capsolver.DefaultTokenPoolSize+3; n != want {
+		t.Errorf("createTask requests = %d, want %d", n, want)
+	}
+}
+
+func TestTokenPoolRefillRecovers(t *testing.T) {
+	srv := capsolvertest.NewServer(t)
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Error: capsolver.ServiceUnavailable})
+	pool := capsolver.NewTokenPool(srv.Session(capsolver.WithRetryPolicy(capsolver.NoRetry)), capsolver.WithTokenRefillBackoff(capsolver.RetryPolicy{BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}))
+	defer pool.Close()
+
+	pool.Warm(turnstile)
+	eventually(t, func() bool { return pool.Err(turnstile) != nil })
+
+	srv.Handle(turnstile.TaskType(), capsolvertest.Behavior{Solution: capsolver.AntiTurnstileSolution{Token: "token"}})
+	time.Sleep(5 * time.Millisecond)
+	eventually(t, func() bool {
+		pool.Warm(turnstile)
+		return pool.Len(turnstile) == capsolver.DefaultTokenPoolSize
+	})
+	if err := pool.Err(turnstile); err != nil {
+		t.Errorf("Err() = %v after a successful refill, want nil", err)
+	}
+}